@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Ширина колонки доски в символах
+const columnWidth = 24
+
+// Функция для работы с канбан-доской текущего проекта
+// Экран обновляется после каждого перемещения, Enter - возврат в главное меню
+func board(scanner *bufio.Scanner, storage storage.Interface) {
+	for {
+		if !printBoard(storage) {
+			return
+		}
+
+		fmt.Println("\n1. Переместить задачу")
+		fmt.Print("\nВведите номер действия (Enter - в главное меню): ")
+		scanner.Scan()
+
+		switch strings.TrimSpace(scanner.Text()) {
+		case "1":
+			moveTask(scanner, storage)
+			waitForEnter(scanner)
+		case "":
+			return
+		default:
+			fmt.Println("\n🔴 Некорректный ввод, попробуйте снова.")
+		}
+	}
+}
+
+// Функция для вывода колонок доски рядом друг с другом
+// Возвращает false, если доску не удалось получить
+func printBoard(storage storage.Interface) bool {
+	ctx, cancel := operation()
+	defer cancel()
+
+	columns, err := storage.Board(ctx)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении доски:", err)
+		return false
+	}
+
+	fmt.Println("\n=============ДОСКА=============")
+	var header, line []string
+	rows := 0
+	for _, col := range columns {
+		header = append(header, cell(fmt.Sprintf("%s [%d] (%d)", col.Status.Name, col.Status.ID, len(col.Tasks))))
+		line = append(line, strings.Repeat("-", columnWidth))
+		rows = max(rows, len(col.Tasks))
+	}
+	fmt.Println(strings.Join(header, " | "))
+	fmt.Println(strings.Join(line, "-+-"))
+
+	for i := 0; i < rows; i++ {
+		var cells []string
+		for _, col := range columns {
+			text := ""
+			if i < len(col.Tasks) {
+				text = boardCard(col.Tasks[i])
+			}
+			cells = append(cells, cell(text))
+		}
+		fmt.Println(strings.Join(cells, " | "))
+	}
+	if rows == 0 {
+		fmt.Println("\n⚠️  На доске пока нет задач.")
+	}
+	fmt.Println("-------------------------------")
+	return true
+}
+
+// Функция для краткой записи задачи на доске: id и заголовок, закрытые отмечены
+func boardCard(task model.Task) string {
+	text := fmt.Sprintf("#%d %s", task.ID, task.Title)
+	if task.IsClosed() {
+		text = "✓ " + text
+	}
+	return text
+}
+
+// Функция для выравнивания текста по ширине колонки, длинный текст обрезается
+func cell(text string) string {
+	n := utf8.RuneCountInString(text)
+	if n > columnWidth {
+		runes := []rune(text)
+		return string(runes[:columnWidth-1]) + "…"
+	}
+	return text + strings.Repeat(" ", columnWidth-n)
+}
+
+// Функция для перемещения задачи в другую колонку или на другое место в колонке
+func moveTask(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID задачи: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	statusID, ok := scanOptionalID(scanner, "🚦 ID статуса колонки (Enter - та же колонка)")
+	if !ok {
+		return
+	}
+	afterID, ok := scanOptionalID(scanner, "⬇️  ID задачи, после которой поставить (Enter - в начало колонки)")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.MoveTask(ctx, taskID, statusID, afterID); err != nil {
+		printError("\n🔴 Ошибка при перемещении задачи:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Задача перемещена!")
+	fmt.Println("-------------------------------")
+}
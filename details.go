@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Функция для просмотра подробностей задачи, её истории и обсуждения в комментариях
+// Экран обновляется после каждого действия, Enter - возврат в главное меню
+func taskDetails(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID задачи: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	for {
+		if !printTaskDetails(storage, taskID) {
+			return
+		}
+
+		fmt.Println("\n1. Добавить комментарий")
+		fmt.Println("2. Изменить комментарий")
+		fmt.Println("3. Удалить комментарий")
+		fmt.Println("4. История изменений")
+		fmt.Print("\nВведите номер действия (Enter - в главное меню): ")
+		scanner.Scan()
+
+		switch strings.TrimSpace(scanner.Text()) {
+		case "1":
+			addComment(scanner, storage, taskID)
+		case "2":
+			editComment(scanner, storage)
+		case "3":
+			deleteComment(scanner, storage)
+		case "4":
+			printHistory(storage, taskID)
+			waitForEnter(scanner)
+		case "":
+			return
+		default:
+			fmt.Println("\n🔴 Некорректный ввод, попробуйте снова.")
+		}
+	}
+}
+
+// Функция для вывода задачи, её блокировок, подзадач и комментариев
+// Возвращает false, если задачу не удалось получить
+func printTaskDetails(storage storage.Interface, taskID int) bool {
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.Tasks(ctx, model.TaskFilter{TaskID: taskID})
+	if err != nil {
+		printError("\n🔴 Ошибка при получении задачи:", err)
+		return false
+	}
+	if len(tasks) == 0 {
+		fmt.Println("\n⚠️  Задача не найдена.")
+		return false
+	}
+
+	fmt.Println("\n===========ЗАДАЧА==============")
+	for _, line := range taskLines(tasks[0]) {
+		fmt.Println(line)
+	}
+
+	tree, err := storage.TaskTree(ctx, taskID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении подзадач:", err)
+		return false
+	}
+	if len(tree) > 0 && len(tree[0].Children) > 0 {
+		closed, total := tree[0].Progress()
+		fmt.Printf("🌳 Подзадачи: %d/%d подзадач закрыто\n", closed, total)
+	}
+
+	blockers, err := storage.Blockers(ctx, taskID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении блокировок:", err)
+		return false
+	}
+	for _, b := range blockers {
+		mark := "🔒"
+		if b.IsClosed() {
+			mark = "🔓"
+		}
+		fmt.Printf("%s Заблокирована задачей %d: %s\n", mark, b.ID, b.Title)
+	}
+
+	comments, err := storage.Comments(ctx, taskID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении комментариев:", err)
+		return false
+	}
+
+	fmt.Println("\n=========КОММЕНТАРИИ===========")
+	if len(comments) == 0 {
+		fmt.Println("💬 Комментариев пока нет.")
+	}
+	for _, c := range comments {
+		edited := ""
+		if !c.Edited.IsZero() {
+			edited = " (изменён " + c.Edited.Format("02.01.2006 15:04") + ")"
+		}
+		fmt.Printf("💬 #%d [%s] %s%s:\n   %s\n",
+			c.ID, c.Created.Format("02.01.2006 15:04"), c.Author.Name, edited, c.Content)
+	}
+	fmt.Println("-------------------------------")
+	return true
+}
+
+// Функция для добавления комментария к задаче
+func addComment(scanner *bufio.Scanner, storage storage.Interface, taskID int) {
+	fmt.Print("\n👤 Введите ID автора: ")
+	scanner.Scan()
+	authorID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n🔴 Ошибка: Некорректный ID автора")
+		return
+	}
+
+	fmt.Print("\n💬 Введите текст комментария: ")
+	scanner.Scan()
+	content := strings.TrimSpace(scanner.Text())
+	if content == "" {
+		fmt.Println("\n🔴 Ошибка: Пустой комментарий")
+		return
+	}
+
+	comment := model.Comment{
+		TaskID:  taskID,
+		Author:  model.User{ID: authorID},
+		Content: content,
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	_, err = storage.NewComment(ctx, comment)
+	if err != nil {
+		printError("\n🔴 Ошибка при создании комментария:", err)
+		return
+	}
+	fmt.Println("\n✅ Комментарий добавлен!")
+}
+
+// Функция для изменения текста комментария
+func editComment(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID комментария: ")
+	scanner.Scan()
+	commentID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	fmt.Print("\n💬 Введите новый текст комментария: ")
+	scanner.Scan()
+	content := strings.TrimSpace(scanner.Text())
+	if content == "" {
+		fmt.Println("\n🔴 Ошибка: Пустой комментарий")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err = storage.UpdateComment(ctx, model.Comment{ID: commentID, Content: content})
+	if err != nil {
+		printError("\n🔴 Ошибка при изменении комментария:", err)
+		return
+	}
+	fmt.Println("\n✅ Комментарий изменён!")
+}
+
+// Функция для удаления комментария
+func deleteComment(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID комментария для удаления: ")
+	scanner.Scan()
+	commentID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err = storage.DeleteComment(ctx, commentID)
+	if err != nil {
+		printError("\n🔴 Ошибка при удалении комментария:", err)
+		return
+	}
+	fmt.Println("\n✅ Комментарий удалён!")
+}
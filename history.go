@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Названия полей задачи для вывода истории
+var fieldNames = map[string]string{
+	model.FieldTitle:    "заголовок",
+	model.FieldContent:  "описание",
+	model.FieldAuthor:   "автор",
+	model.FieldAssignee: "исполнитель",
+	model.FieldStatus:   "статус",
+	model.FieldPriority: "приоритет",
+	model.FieldDue:      "срок",
+	model.FieldParent:   "родительская задача",
+	model.FieldProject:  "проект",
+	model.FieldSprint:   "спринт",
+}
+
+// Функция для вывода истории изменений задачи: кто, когда и что изменил
+func printHistory(storage storage.Interface, taskID int) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	changes, err := storage.History(ctx, taskID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении истории задачи:", err)
+		return
+	}
+
+	fmt.Println("\n===========ИСТОРИЯ=============")
+	if len(changes) == 0 {
+		fmt.Println("📜 Изменений пока нет.")
+	}
+	for _, c := range changes {
+		fmt.Printf("📜 [%s] %s: %s\n", c.Time.Format("02.01.2006 15:04"), c.Actor.Name, describeChange(c))
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для описания одного изменения задачи
+func describeChange(c model.Change) string {
+	switch c.Action {
+	case model.ActionCreate:
+		return fmt.Sprintf("создал задачу «%s»", c.After)
+	case model.ActionDelete:
+		return fmt.Sprintf("переместил задачу «%s» в корзину", c.Before)
+	case model.ActionRestore:
+		return "восстановил задачу из корзины"
+	case model.ActionPurge:
+		return fmt.Sprintf("удалил задачу «%s» навсегда", c.Before)
+	case model.ActionClose:
+		return "закрыл задачу"
+	case model.ActionReopen:
+		return "переоткрыл задачу"
+	case model.ActionLabel:
+		return fmt.Sprintf("добавил метку %s", c.After)
+	case model.ActionUnlabel:
+		return fmt.Sprintf("снял метку %s", c.Before)
+	}
+
+	name, ok := fieldNames[c.Field]
+	if !ok {
+		name = c.Field
+	}
+	return fmt.Sprintf("изменил %s: «%s» → «%s»", name, c.Before, c.After)
+}
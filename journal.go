@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Число последних операций сессии, которые можно отменить
+const journalSize = 20
+
+// Виды операций в журнале
+const (
+	opCreate  = "create"
+	opUpdate  = "update"
+	opDelete  = "delete"
+	opLabel   = "label"
+	opUnlabel = "unlabel"
+)
+
+// step — Операция сессии, которую можно отменить и повторить
+type step struct {
+	kind    string
+	taskID  int
+	labelID int
+	// Задача до и после операции обновления
+	before, after model.Task
+	// Версия задачи после последнего применения или отмены обновления,
+	// если задачу с тех пор изменили, отмена завершится конфликтом версий
+	version int
+}
+
+// journal — Журнал операций сессии: выполненные операции и отменённые для повтора
+type journal struct {
+	done   []step
+	undone []step
+}
+
+// Журнал текущей сессии
+var session journal
+
+// add — Запись выполненной операции, отменённые операции больше нельзя повторить
+func (j *journal) add(s step) {
+	j.done = append(j.done, s)
+	if len(j.done) > journalSize {
+		j.done = j.done[len(j.done)-journalSize:]
+	}
+	j.undone = nil
+}
+
+// title — Описание операции для экрана отмены
+func (s step) title() string {
+	switch s.kind {
+	case opCreate:
+		return fmt.Sprintf("создание задачи %d", s.taskID)
+	case opUpdate:
+		return fmt.Sprintf("обновление задачи %d", s.taskID)
+	case opDelete:
+		return fmt.Sprintf("удаление задачи %d", s.taskID)
+	case opLabel:
+		return fmt.Sprintf("добавление метки %d к задаче %d", s.labelID, s.taskID)
+	case opUnlabel:
+		return fmt.Sprintf("снятие метки %d с задачи %d", s.labelID, s.taskID)
+	}
+	return s.kind
+}
+
+// Функция для отмены последней операции сессии
+func undoStep(scanner *bufio.Scanner, storage storage.Interface) {
+	if len(session.done) == 0 {
+		fmt.Println("\n⚠️  Нечего отменять.")
+		return
+	}
+	s := session.done[len(session.done)-1]
+	fmt.Printf("\n↩️  Отмена: %s\n", s.title())
+	if !replay(scanner, storage, &s, true) {
+		return
+	}
+	session.done = session.done[:len(session.done)-1]
+	session.undone = append(session.undone, s)
+	fmt.Println("\n✅ Операция отменена!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для повтора последней отменённой операции
+func redoStep(scanner *bufio.Scanner, storage storage.Interface) {
+	if len(session.undone) == 0 {
+		fmt.Println("\n⚠️  Нечего повторять.")
+		return
+	}
+	s := session.undone[len(session.undone)-1]
+	fmt.Printf("\n↪️  Повтор: %s\n", s.title())
+	if !replay(scanner, storage, &s, false) {
+		return
+	}
+	session.undone = session.undone[:len(session.undone)-1]
+	session.done = append(session.done, s)
+	fmt.Println("\n✅ Операция повторена!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для отмены (undo) или повтора операции после подтверждения
+// Показывает, что именно изменится, возвращает true, если операция выполнена
+func replay(scanner *bufio.Scanner, storage storage.Interface, s *step, undo bool) bool {
+	// Создание отменяется перемещением в корзину, удаление - восстановлением
+	trash := (s.kind == opCreate && undo) || (s.kind == opDelete && !undo)
+	// Метка снимается при отмене добавления и при повторе снятия
+	detach := (s.kind == opLabel && undo) || (s.kind == opUnlabel && !undo)
+
+	var target model.Task
+	fmt.Println("-------------------------------")
+	switch s.kind {
+	case opCreate, opDelete:
+		if trash {
+			fmt.Printf("🗑️  Задача %d будет перемещена в корзину\n", s.taskID)
+		} else {
+			fmt.Printf("♻️  Задача %d будет восстановлена из корзины\n", s.taskID)
+		}
+	case opUpdate:
+		current, ok := loadTask(storage, s.taskID)
+		if !ok {
+			return false
+		}
+		if current.Version != s.version {
+			fmt.Println("⚠️  Задачу изменили после этой операции, сохранить не получится.")
+		}
+		src := s.after
+		if undo {
+			src = s.before
+		}
+		ctx, cancel := operation()
+		var warning string
+		var err error
+		target, warning, err = replayTarget(ctx, storage, current, src)
+		cancel()
+		if err != nil {
+			printError("\n🔴 Ошибка при получении разрешённых статусов:", err)
+			return false
+		}
+		if warning != "" {
+			fmt.Println("⚠️  " + warning)
+		}
+		target.Version = s.version
+		fmt.Printf("✏️  Задача %d будет изменена:\n", s.taskID)
+		printChanges(current, target)
+	case opLabel, opUnlabel:
+		if detach {
+			fmt.Printf("🏷️  С задачи %d будет снята метка %d\n", s.taskID, s.labelID)
+		} else {
+			fmt.Printf("🏷️  К задаче %d будет добавлена метка %d\n", s.taskID, s.labelID)
+		}
+	}
+	fmt.Println("-------------------------------")
+
+	fmt.Print("\n❓ Выполнить? (y/N): ")
+	scanner.Scan()
+	if !strings.EqualFold(strings.TrimSpace(scanner.Text()), "y") {
+		fmt.Println("\n✋ Ничего не изменено.")
+		return false
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	var err error
+	switch s.kind {
+	case opCreate, opDelete:
+		err = trashOrRestore(ctx, storage, s.taskID, trash)
+	case opUpdate:
+		err = storage.UpdateTask(ctx, target)
+	case opLabel, opUnlabel:
+		if detach {
+			err = storage.DetachLabel(ctx, s.taskID, s.labelID)
+		} else {
+			err = storage.AttachLabel(ctx, s.taskID, s.labelID)
+		}
+	}
+	if err != nil {
+		printError("\n🔴 Ошибка при выполнении операции:", err)
+		fmt.Println("-------------------------------")
+		return false
+	}
+	if s.kind == opUpdate {
+		s.version++
+	}
+	return true
+}
+
+// Функция для перемещения задачи в корзину или восстановления из неё
+func trashOrRestore(ctx context.Context, storage storage.Interface, taskID int, trash bool) error {
+	if trash {
+		return storage.DeleteTask(ctx, taskID)
+	}
+	return storage.RestoreTask(ctx, taskID)
+}
+
+// Функция для задачи, которую сохранит отмена или повтор обновления: поля src в задаче current
+// Если процесс работы не разрешает переход в статус src, статус остаётся текущим,
+// а для экрана подтверждения возвращается предупреждение
+func replayTarget(ctx context.Context, storage storage.Interface, current, src model.Task) (model.Task, string, error) {
+	target := withFields(current, src)
+	if target.StatusID == current.StatusID {
+		return target, "", nil
+	}
+	next, err := storage.NextStatuses(ctx, current.StatusID)
+	if err != nil {
+		return model.Task{}, "", err
+	}
+	for _, st := range next {
+		if st.ID == target.StatusID {
+			return target, "", nil
+		}
+	}
+	warning := fmt.Sprintf("Переход «%s» → «%s» не разрешён, статус останется «%s».", current.Status, target.Status, current.Status)
+	target.StatusID, target.Status = current.StatusID, current.Status
+	return target, warning, nil
+}
+
+// Функция для переноса редактируемых полей src в задачу task
+func withFields(task, src model.Task) model.Task {
+	task.Title = src.Title
+	task.Content = src.Content
+	task.AuthorID = src.AuthorID
+	task.AssignedID = src.AssignedID
+	task.Priority = src.Priority
+	task.Due = src.Due
+	task.ParentID = src.ParentID
+	task.StatusID = src.StatusID
+	task.Status = src.Status
+	return task
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage/memdb"
+)
+
+func TestReplayTarget_Status(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.New()
+	id, err := db.NewTask(ctx, model.Task{Title: "старый"}, nil)
+	if err != nil {
+		t.Fatalf("NewTask() error = %v", err)
+	}
+
+	// update меняет статус и заголовок и возвращает задачу до и после, как в журнале
+	update := func(statusID int, title string) (before, after model.Task) {
+		t.Helper()
+		before = loadTestTask(t, db, id)
+		task := before
+		task.StatusID = statusID
+		task.Title = title
+		if err := db.UpdateTask(ctx, task); err != nil {
+			t.Fatalf("UpdateTask() error = %v", err)
+		}
+		after = loadTestTask(t, db, id)
+		return before, after
+	}
+
+	// Отмена перехода backlog → in progress: обратный переход разрешён
+	before, current := update(2, "новый")
+	target, warning, err := replayTarget(ctx, db, current, before)
+	if err != nil || warning != "" {
+		t.Fatalf("replayTarget() = %q, %v, want без предупреждения", warning, err)
+	}
+	if target.StatusID != before.StatusID || target.Title != "старый" {
+		t.Errorf("replayTarget() = %+v, want статус %d и прежний заголовок", target, before.StatusID)
+	}
+	if err := db.UpdateTask(ctx, target); err != nil {
+		t.Fatalf("UpdateTask() отмены error = %v", err)
+	}
+
+	// Отмена перехода review → done: переход done → review запрещён,
+	// статус остаётся прежним, остальные поля восстанавливаются
+	update(2, "a")
+	update(3, "b")
+	before, current = update(4, "c")
+	target, warning, err = replayTarget(ctx, db, current, before)
+	if err != nil || warning == "" {
+		t.Fatalf("replayTarget() = %q, %v, want предупреждение о запрещённом переходе", warning, err)
+	}
+	if target.StatusID != current.StatusID || target.Status != current.Status || target.Title != "b" {
+		t.Errorf("replayTarget() = %+v, want статус %d и заголовок «b»", target, current.StatusID)
+	}
+	if err := db.UpdateTask(ctx, target); err != nil {
+		t.Errorf("UpdateTask() отмены с запрещённым переходом error = %v", err)
+	}
+}
+
+// loadTestTask возвращает задачу по id.
+func loadTestTask(t *testing.T, db *memdb.DB, id int) model.Task {
+	t.Helper()
+	tasks, err := db.Tasks(context.Background(), model.TaskFilter{TaskID: id})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("Tasks(TaskID: %d) = %v, %v", id, tasks, err)
+	}
+	return tasks[0]
+}
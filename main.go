@@ -2,18 +2,38 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"time"
 
+	"task-meneger/pkg/model"
 	"task-meneger/pkg/storage"
 	"task-meneger/pkg/storage/postgres"
 
 	"github.com/joho/godotenv"
 )
 
+// Задачи со сроком в ближайшие dueSoon считаются горящими
+const dueSoon = 24 * time.Hour
+
+// Число задач на одной странице списка
+const pageSize = 10
+
+// Время, отведённое на одну операцию с БД, переопределяется через DB_TIMEOUT
+var opTimeout = 5 * time.Second
+
+// Пользователь, от имени которого вносятся изменения, задаётся через TASK_USER
+var currentUser int
+
+// Текущий проект сессии, списки и поиск задач ограничиваются им
+var currentProject = model.Project{ID: model.DefaultProject}
+
 func main() {
 
 	// Загрузка переменных окружения из env
@@ -22,21 +42,45 @@ func main() {
 		log.Println("Не удалось загрузить .env файл, используем переменные окружения")
 	}
 
+	if v := os.Getenv("DB_TIMEOUT"); v != "" {
+		opTimeout, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Некорректное значение DB_TIMEOUT: %v", err)
+		}
+	}
+
+	if v := os.Getenv("TASK_USER"); v != "" {
+		currentUser, err = strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("Некорректное значение TASK_USER: %v", err)
+		}
+	}
+
+	// Команда migrate выполняется без меню
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	// Подключение к БД
+	ctx, cancel := operation()
 	var storage storage.Interface
-	storage, err = postgres.New()
+	storage, err = postgres.New(ctx)
+	cancel()
 	if err != nil {
 		log.Fatalf("Ошибка подключения к БД: %v", err)
 	}
 	defer storage.Close()
+	loadCurrentProject(storage)
 
 	// Приветствие и вывод меню в терминале
 	fmt.Println("-------------------------------")
 	fmt.Println("Добро пожаловать в Task Manager!")
+	fmt.Printf("Вы работаете как пользователь %d\n", currentUser)
 	fmt.Println("-------------------------------")
 	scanner := bufio.NewScanner(os.Stdin)
 
 	for {
+		fmt.Printf("\n📁 Проект: %s (ID: %d)\n", currentProject.Name, currentProject.ID)
 		fmt.Println("\n=============TASK==============")
 		fmt.Println("1. Посмотреть список задач")
 		fmt.Println("2. Создать новую задачу")
@@ -50,8 +94,43 @@ func main() {
 		fmt.Println("8. Создать нового пользователя")
 		fmt.Println("\n============SEARCH=============")
 		fmt.Println("9. Поиск задач по автору")
+		fmt.Println("\n==========TASK STATE===========")
+		fmt.Println("11. Закрыть задачу")
+		fmt.Println("12. Переоткрыть задачу")
+		fmt.Println("13. Просроченные и горящие задачи")
+		fmt.Println("\n===========STATUSES============")
+		fmt.Println("14. Посмотреть статусы и переходы")
+		fmt.Println("15. Создать новый статус")
+		fmt.Println("16. Разрешить переход между статусами")
+		fmt.Println("17. Запретить переход между статусами")
+		fmt.Println("\n=========DEPENDENCIES==========")
+		fmt.Println("18. Добавить блокирующую задачу")
+		fmt.Println("19. Снять блокировку задачи")
+		fmt.Println("20. Задачи, готовые к работе")
+		fmt.Println("\n===========DETAILS=============")
+		fmt.Println("21. Подробности задачи и комментарии")
+		fmt.Println("\n========LABEL MANAGEMENT=======")
+		fmt.Println("22. Добавить метку к задаче")
+		fmt.Println("23. Снять метку с задачи")
+		fmt.Println("24. Переименовать метку")
+		fmt.Println("25. Удалить метку")
+		fmt.Println("\n========ADVANCED SEARCH========")
+		fmt.Println("26. Поиск задач по фильтрам")
+		fmt.Println("27. Поиск задач по тексту")
+		fmt.Println("\n=============TRASH=============")
+		fmt.Println("28. Корзина")
+		fmt.Println("\n=============UNDO==============")
+		fmt.Println("29. Отменить последнее действие")
+		fmt.Println("30. Повторить отменённое действие")
+		fmt.Println("\n===========PROJECTS============")
+		fmt.Println("31. Проекты и участники")
+		fmt.Println("32. Сменить текущий проект")
+		fmt.Println("\n=============BOARD=============")
+		fmt.Println("33. Канбан-доска")
+		fmt.Println("\n============SPRINTS============")
+		fmt.Println("34. Спринты")
 
-		fmt.Println("10. Выйти")
+		fmt.Println("\n10. Выйти")
 
 		fmt.Print("\nВведите номер действия: ")
 		scanner.Scan()
@@ -59,8 +138,7 @@ func main() {
 
 		switch choice {
 		case "1":
-			printTasks(storage)
-			waitForEnter(scanner)
+			printTasks(scanner, storage)
 		case "2":
 			createTask(scanner, storage)
 			waitForEnter(scanner)
@@ -85,6 +163,73 @@ func main() {
 		case "9":
 			getTasksByIdUser(scanner, storage)
 			waitForEnter(scanner)
+		case "11":
+			closeTask(scanner, storage)
+			waitForEnter(scanner)
+		case "12":
+			reopenTask(scanner, storage)
+			waitForEnter(scanner)
+		case "13":
+			printOverdueTasks(storage)
+			waitForEnter(scanner)
+		case "14":
+			printStatuses(storage)
+			waitForEnter(scanner)
+		case "15":
+			createStatus(scanner, storage)
+			waitForEnter(scanner)
+		case "16":
+			addTransition(scanner, storage)
+			waitForEnter(scanner)
+		case "17":
+			deleteTransition(scanner, storage)
+			waitForEnter(scanner)
+		case "18":
+			addDependency(scanner, storage)
+			waitForEnter(scanner)
+		case "19":
+			removeDependency(scanner, storage)
+			waitForEnter(scanner)
+		case "20":
+			printUnblockedTasks(storage)
+			waitForEnter(scanner)
+		case "21":
+			taskDetails(scanner, storage)
+		case "22":
+			attachLabel(scanner, storage)
+			waitForEnter(scanner)
+		case "23":
+			detachLabel(scanner, storage)
+			waitForEnter(scanner)
+		case "24":
+			renameLabel(scanner, storage)
+			waitForEnter(scanner)
+		case "25":
+			deleteLabel(scanner, storage)
+			waitForEnter(scanner)
+		case "26":
+			searchTasks(scanner, storage)
+			waitForEnter(scanner)
+		case "27":
+			fullTextSearch(scanner, storage)
+			waitForEnter(scanner)
+		case "28":
+			trash(scanner, storage)
+		case "29":
+			undoStep(scanner, storage)
+			waitForEnter(scanner)
+		case "30":
+			redoStep(scanner, storage)
+			waitForEnter(scanner)
+		case "31":
+			manageProjects(scanner, storage)
+		case "32":
+			switchProject(scanner, storage)
+			waitForEnter(scanner)
+		case "33":
+			board(scanner, storage)
+		case "34":
+			sprints(scanner, storage)
 
 		case "10":
 			fmt.Println("Выход...")
@@ -102,30 +247,250 @@ func waitForEnter(scanner *bufio.Scanner) {
 	scanner.Scan()
 }
 
+// Функция для вывода ошибки хранилища
+// Для известных ошибок после текста ошибки выводится подсказка
+func printError(msg string, err error) {
+	fmt.Println(msg, err)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		fmt.Println("⚠️  Запись с таким ID не найдена.")
+	case errors.Is(err, storage.ErrReferenced):
+		fmt.Println("🔗 Указан ID несуществующей задачи, пользователя, метки или статуса.")
+	case errors.Is(err, storage.ErrConflict):
+		fmt.Println("⛔ Операция невозможна в текущем состоянии записи.")
+	case errors.Is(err, storage.ErrInvalid):
+		fmt.Println("✏️  Проверьте введённые данные.")
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Println("⏱️  БД не ответила вовремя, попробуйте ещё раз.")
+	case errors.Is(err, context.Canceled):
+		fmt.Println("✋ Операция отменена.")
+	}
+}
+
 // Функция для вывода списка задач
-func printTasks(storage storage.Interface) {
-	tasks, err := storage.Tasks(0, 0) // Получаем все задачи
-	if err != nil {
-		fmt.Println("-------------------------------")
-		fmt.Println("\n🔴 Ошибка при получении списка задач:", err)
+// Перед выводом спрашиваем, какие задачи показывать и как их сортировать,
+// затем выводим задачи постранично с переходом вперёд и назад
+func printTasks(scanner *bufio.Scanner, storage storage.Interface) {
+	filter := model.TaskFilter{State: scanState(scanner), Limit: pageSize}
+	filter.Sort, filter.Desc = scanSort(scanner)
+
+	for page := 1; ; {
+		ctx, cancel := operation()
+		tasks, err := storage.Tasks(ctx, filter)
+		if err != nil {
+			cancel()
+			fmt.Println("-------------------------------")
+			printError("\n🔴 Ошибка при получении списка задач:", err)
+			fmt.Println("-------------------------------")
+			return
+		}
+
+		if len(tasks) == 0 {
+			cancel()
+			fmt.Println("-------------------------------")
+			fmt.Println("\n⚠️  Задач пока нет.")
+			fmt.Println("-------------------------------")
+			return
+		}
+
+		progress, err := subtaskProgress(ctx, storage, tasks)
+		cancel()
+		if err != nil {
+			fmt.Println("-------------------------------")
+			printError("\n🔴 Ошибка при получении подзадач:", err)
+			fmt.Println("-------------------------------")
+			return
+		}
+
+		fmt.Printf("\n📋 Список задач, страница %d:\n", page)
+		for _, node := range model.BuildTree(tasks) {
+			fmt.Println("-------------------------------")
+			printTaskNode(node, "", "", "", progress)
+		}
 		fmt.Println("-------------------------------")
-		return
+
+		// Переход по страницам: курсором служит первая или последняя задача страницы
+		fmt.Print("\n➡️  n - следующая страница, ⬅️  p - предыдущая, Enter - в главное меню: ")
+		scanner.Scan()
+		next := filter
+		switch strings.TrimSpace(scanner.Text()) {
+		case "n":
+			next.After, next.Before = tasks[len(tasks)-1].ID, 0
+		case "p":
+			next.After, next.Before = 0, tasks[0].ID
+		default:
+			return
+		}
+
+		ctx, cancel = operation()
+		more, err := storage.Tasks(ctx, next)
+		cancel()
+		if err != nil {
+			printError("\n🔴 Ошибка при получении списка задач:", err)
+			return
+		}
+		if len(more) == 0 {
+			fmt.Println("\n⚠️  Других страниц в этом направлении нет.")
+			continue
+		}
+		filter = next
+		if next.After != 0 {
+			page++
+		} else {
+			page--
+		}
 	}
+}
 
-	if len(tasks) == 0 {
-		fmt.Println("-------------------------------")
-		fmt.Println("\n⚠️  Задач пока нет.")
-		fmt.Println("-------------------------------")
-		return
+// Функция для получения контекста одной операции с БД
+// Операция прерывается по истечении opTimeout или по Ctrl+C,
+// при этом само приложение продолжает работать
+// Изменения выполняются от имени currentUser и попадают в историю задач
+func operation() (context.Context, context.CancelFunc) {
+	ctx := storage.WithActor(context.Background(), currentUser)
+	ctx = storage.WithProject(ctx, currentProject.ID)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	ctx, cancel := context.WithTimeout(ctx, opTimeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// Функция для выбора поля и направления сортировки задач
+func scanSort(scanner *bufio.Scanner) (string, bool) {
+	fmt.Println("-------------------------------")
+	fmt.Println("1. По ID")
+	fmt.Println("2. По времени создания")
+	fmt.Println("3. По времени выполнения")
+	fmt.Println("4. По заголовку")
+	fmt.Print("\n↕️  Как отсортировать задачи (по умолчанию по ID): ")
+	scanner.Scan()
+
+	sort := model.SortID
+	switch strings.TrimSpace(scanner.Text()) {
+	case "2":
+		sort = model.SortOpened
+	case "3":
+		sort = model.SortClosed
+	case "4":
+		sort = model.SortTitle
 	}
 
-	fmt.Println("\n📋 Список задач:")
+	fmt.Print("\n↕️  По убыванию? (y/N): ")
+	scanner.Scan()
+	desc := strings.EqualFold(strings.TrimSpace(scanner.Text()), "y")
+	return sort, desc
+}
+
+// Функция для подсчёта закрытых подзадач у задач страницы
+// Подзадачи могут оказаться на других страницах, поэтому лес задач
+// проекта запрашивается целиком одним запросом
+func subtaskProgress(ctx context.Context, storage storage.Interface, tasks []model.Task) (map[int]string, error) {
+	forest, err := storage.TaskTree(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[int]*model.TaskNode)
+	var index func([]*model.TaskNode)
+	index = func(list []*model.TaskNode) {
+		for _, n := range list {
+			nodes[n.Task.ID] = n
+			index(n.Children)
+		}
+	}
+	index(forest)
+
+	progress := make(map[int]string)
 	for _, task := range tasks {
-		fmt.Println("-------------------------------")
-		fmt.Printf("🆔 ID: %d\n📌 Заголовок: %s\n📝 Описание: %s\n👤 Автор: %d\n🎯 Исполнитель: %d\n",
-			task.ID, task.Title, task.Content, task.AuthorID, task.AssignedID)
+		if n := nodes[task.ID]; n != nil && len(n.Children) > 0 {
+			closed, total := n.Progress()
+			progress[task.ID] = fmt.Sprintf("%d/%d подзадач закрыто", closed, total)
+		}
 	}
+	return progress, nil
+}
+
+// Функция для выбора состояния задач: открытые, закрытые или все
+func scanState(scanner *bufio.Scanner) int {
 	fmt.Println("-------------------------------")
+	fmt.Println("1. Только открытые")
+	fmt.Println("2. Только закрытые")
+	fmt.Println("3. Все задачи")
+	fmt.Print("\n🔎 Какие задачи показать (по умолчанию все): ")
+	scanner.Scan()
+
+	switch strings.TrimSpace(scanner.Text()) {
+	case "1":
+		return model.StateOpen
+	case "2":
+		return model.StateClosed
+	}
+	return model.StateAll
+}
+
+// Функция для вывода задачи и её подзадач в виде дерева
+// branch - отступ перед первой строкой задачи, indent - перед остальными строками
+func printTaskNode(node *model.TaskNode, prefix, branch, indent string, progress map[int]string) {
+	task := node.Task
+	lines := taskLines(task)
+	if p, ok := progress[task.ID]; ok {
+		lines = append(lines, "🌳 Подзадачи: "+p)
+	}
+
+	for i, line := range lines {
+		if i == 0 {
+			fmt.Println(prefix + branch + line)
+			continue
+		}
+		fmt.Println(prefix + indent + line)
+	}
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printTaskNode(child, prefix+indent, "└── ", "    ", progress)
+			continue
+		}
+		printTaskNode(child, prefix+indent, "├── ", "│   ", progress)
+	}
+}
+
+// Функция для получения строк с описанием задачи
+func taskLines(task model.Task) []string {
+	return []string{
+		fmt.Sprintf("🆔 ID: %d", task.ID),
+		fmt.Sprintf("📁 Проект: %d", task.ProjectID),
+		fmt.Sprintf("📌 Заголовок: %s", task.Title),
+		fmt.Sprintf("📝 Описание: %s", task.Content),
+		fmt.Sprintf("👤 Автор: %d", task.AuthorID),
+		fmt.Sprintf("🎯 Исполнитель: %d", task.AssignedID),
+		fmt.Sprintf("🚦 Статус: %s", task.Status),
+		fmt.Sprintf("⚡ Приоритет: %s", priorityName(task.Priority)),
+		fmt.Sprintf("📅 Срок: %s", dueDate(task)),
+		fmt.Sprintf("🏃 Спринт: %s", taskSprint(task)),
+		fmt.Sprintf("🏷️  Метки: %s", labelNames(task.Labels)),
+		taskState(task),
+	}
+}
+
+// Функция для вывода названий меток через запятую
+func labelNames(labels []model.Label) string {
+	if len(labels) == 0 {
+		return "нет"
+	}
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Функция для вывода состояния задачи (открыта или закрыта и когда)
+func taskState(task model.Task) string {
+	if !task.IsClosed() {
+		return "🟢 Состояние: открыта"
+	}
+	return fmt.Sprintf("✅ Состояние: закрыта %s", task.Closed.Format("02.01.2006 15:04"))
 }
 
 // Функция для создания новой задачи
@@ -163,6 +528,16 @@ func createTask(scanner *bufio.Scanner, storage storage.Interface) {
 		return
 	}
 
+	priority, due, ok := scanPriorityAndDue(scanner)
+	if !ok {
+		return
+	}
+
+	parentID, ok := scanParentID(scanner)
+	if !ok {
+		return
+	}
+
 	// Ввод меток (можно несколько через запятую)
 	fmt.Println("-------------------------------")
 	fmt.Print("\n🏷️  Введите ID меток через запятую (или оставьте пустым): ")
@@ -186,21 +561,29 @@ func createTask(scanner *bufio.Scanner, storage storage.Interface) {
 	}
 
 	// Создаём задачу
-	task := postgres.Task{
+	task := model.Task{
 		Title:      title,
 		Content:    content,
 		AuthorID:   authorID,
 		AssignedID: assignedID,
+		Priority:   priority,
+		Due:        due,
+		ParentID:   parentID,
 	}
 
-	id, err := storage.NewTask(task, labelIDs)
+	ctx, cancel := operation()
+	defer cancel()
+
+	id, err := storage.NewTask(ctx, task, labelIDs)
 	if err != nil {
 		fmt.Println("-------------------------------")
-		fmt.Println("\n🔴 Ошибка при создании задачи:", err)
+		printError("\n🔴 Ошибка при создании задачи:", err)
 		fmt.Println("-------------------------------")
 		return
 	}
 
+	session.add(step{kind: opCreate, taskID: id})
+
 	fmt.Println("-------------------------------")
 	fmt.Printf("\n✅ Задача успешно создана! ID: %d\n", id)
 	fmt.Println("-------------------------------")
@@ -214,6 +597,16 @@ func updateTask(scanner *bufio.Scanner, storage storage.Interface) {
 	scanner.Scan()
 	taskID, _ := strconv.Atoi(scanner.Text()) // Преобразуем ввод в число
 
+	// Запоминаем задачу до правок, чтобы при сохранении заметить чужие изменения
+	original, ok := loadTask(storage, taskID)
+	if !ok {
+		return
+	}
+	fmt.Println("\n📄 Текущие данные задачи:")
+	for _, line := range taskLines(original) {
+		fmt.Println(line)
+	}
+
 	fmt.Print("\n📌 Введите новый заголовок задачи: ")
 	fmt.Println("-------------------------------")
 	scanner.Scan()
@@ -234,24 +627,283 @@ func updateTask(scanner *bufio.Scanner, storage storage.Interface) {
 	scanner.Scan()
 	assignedID, _ := strconv.Atoi(scanner.Text())
 
-	task := postgres.Task{
+	priority, due, ok := scanPriorityAndDue(scanner)
+	if !ok {
+		return
+	}
+
+	parentID, ok := scanParentID(scanner)
+	if !ok {
+		return
+	}
+
+	status, ok := chooseStatus(scanner, storage, original)
+	if !ok {
+		return
+	}
+
+	task := model.Task{
 		ID:         taskID,
 		Title:      title,
 		Content:    content,
 		AuthorID:   authorID,
 		AssignedID: assignedID,
+		Priority:   priority,
+		Due:        due,
+		ParentID:   parentID,
+		StatusID:   status.ID,
+		Status:     status.Name,
+		Version:    original.Version,
 	}
+	saveTask(scanner, storage, original, task)
+}
 
-	err := storage.UpdateTask(task)
-	if err != nil {
-		fmt.Println("-------------------------------")
-		fmt.Println("\n🔴 Ошибка при обновлении задачи:", err)
+// Функция для сохранения изменённой задачи
+// Если задачу изменили после загрузки, показываем, что изменилось,
+// и предлагаем загрузить её заново или сохранить свои правки поверх
+func saveTask(scanner *bufio.Scanner, storage storage.Interface, original, task model.Task) {
+	for {
+		ctx, cancel := operation()
+		err := storage.UpdateTask(ctx, task)
+		cancel()
+
+		var trErr *model.TransitionError
+		switch {
+		case errors.Is(err, model.ErrVersionConflict):
+			current, ok := loadTask(storage, task.ID)
+			if !ok {
+				return
+			}
+			fmt.Println("-------------------------------")
+			fmt.Println("\n⚠️  Задачу изменили, пока вы её редактировали:")
+			printChanges(original, current)
+			fmt.Println("\n1. Загрузить задачу заново и перенести в неё ваши правки")
+			fmt.Println("2. Сохранить ваши правки поверх")
+			fmt.Print("\nВыберите действие (Enter - отмена): ")
+			scanner.Scan()
+
+			switch strings.TrimSpace(scanner.Text()) {
+			case "1":
+				// Изменённые пользователем поля переносятся в актуальную версию,
+				// остальные поля остаются такими, как их сохранили другие
+				rebased := rebaseEdits(original, task, current)
+				if len(model.Diff(current, rebased)) == 0 {
+					fmt.Println("\n⚠️  Ваши правки уже есть в актуальной версии задачи.")
+					return
+				}
+				fmt.Println("\n📄 Ваши правки поверх актуальной версии:")
+				printChanges(current, rebased)
+				fmt.Print("\n❓ Сохранить? (y/N): ")
+				scanner.Scan()
+				if !strings.EqualFold(strings.TrimSpace(scanner.Text()), "y") {
+					fmt.Println("\n✋ Изменения не сохранены.")
+					return
+				}
+				original, task = current, rebased
+				continue
+			case "2":
+				// Повторяем сохранение от текущей версии
+				original = current
+				task.Version = current.Version
+				continue
+			default:
+				fmt.Println("\n✋ Изменения не сохранены.")
+				return
+			}
+		case errors.As(err, &trErr):
+			fmt.Println("-------------------------------")
+			fmt.Println("\n🔴 Такой переход статуса запрещён:", err)
+			fmt.Println("-------------------------------")
+			return
+		case err != nil:
+			fmt.Println("-------------------------------")
+			printError("\n🔴 Ошибка при обновлении задачи:", err)
+			fmt.Println("-------------------------------")
+			return
+		}
+
+		// Полные данные задачи после сохранения нужны для отмены и повтора
+		if saved, ok := loadTask(storage, task.ID); ok {
+			session.add(step{kind: opUpdate, taskID: task.ID, before: original, after: saved, version: task.Version + 1})
+		}
+
+		fmt.Println("\n✅ Задача успешно обновлена!")
 		fmt.Println("-------------------------------")
 		return
 	}
+}
+
+// Функция для переноса правок пользователя в актуальную версию задачи
+// Переносятся только поля, в которых edited отличается от original,
+// статус 0 в edited означает, что статус не меняли
+func rebaseEdits(original, edited, current model.Task) model.Task {
+	if edited.Title != original.Title {
+		current.Title = edited.Title
+	}
+	if edited.Content != original.Content {
+		current.Content = edited.Content
+	}
+	if edited.AuthorID != original.AuthorID {
+		current.AuthorID = edited.AuthorID
+	}
+	if edited.AssignedID != original.AssignedID {
+		current.AssignedID = edited.AssignedID
+	}
+	if edited.StatusID != 0 && edited.StatusID != original.StatusID {
+		current.StatusID, current.Status = edited.StatusID, edited.Status
+	}
+	if edited.Priority != original.Priority {
+		current.Priority = edited.Priority
+	}
+	if !edited.Due.Equal(original.Due) {
+		current.Due = edited.Due
+	}
+	if edited.ParentID != original.ParentID {
+		current.ParentID = edited.ParentID
+	}
+	return current
+}
+
+// Функция для вывода полей задачи, которые отличаются в двух её версиях
+func printChanges(before, after model.Task) {
+	old, cur := taskLines(before), taskLines(after)
+	for i := range cur {
+		if old[i] != cur[i] {
+			fmt.Println("   было: ", old[i])
+			fmt.Println("   стало:", cur[i])
+		}
+	}
+}
+
+// Функция для загрузки задачи по ID
+// Ошибки и отсутствие задачи выводятся в терминал
+func loadTask(storage storage.Interface, taskID int) (model.Task, bool) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.Tasks(ctx, model.TaskFilter{TaskID: taskID})
+	if err != nil {
+		printError("\n🔴 Ошибка при получении задачи:", err)
+		return model.Task{}, false
+	}
+	if len(tasks) == 0 {
+		fmt.Println("\n⚠️  Задача не найдена.")
+		return model.Task{}, false
+	}
+	return tasks[0], true
+}
+
+// Функция для ввода приоритета и срока выполнения задачи
+// Пустой ввод - обычный приоритет и задача без срока
+func scanPriorityAndDue(scanner *bufio.Scanner) (int, time.Time, bool) {
+	fmt.Println("-------------------------------")
+	fmt.Print("\n⚡ Введите приоритет (0 - низкий, 1 - обычный, 2 - высокий, 3 - критический): ")
+	fmt.Println("-------------------------------")
+	scanner.Scan()
+	priority := model.PriorityNormal
+	if input := strings.TrimSpace(scanner.Text()); input != "" {
+		p, err := strconv.Atoi(input)
+		if err != nil || p < model.PriorityLow || p > model.PriorityCritical {
+			fmt.Println("🔴 Ошибка: Некорректный приоритет")
+			fmt.Println("-------------------------------")
+			return 0, time.Time{}, false
+		}
+		priority = p
+	}
+
+	// Срок - до конца указанного дня
+	due, ok := scanDate(scanner, "📅 Введите срок выполнения ДД.ММ.ГГГГ (или оставьте пустым)", true)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	return priority, due, true
+}
+
+// Функция для ввода родительской задачи
+// Пустой ввод или 0 - задача верхнего уровня
+func scanParentID(scanner *bufio.Scanner) (int, bool) {
+	fmt.Print("\n🌳 Введите ID родительской задачи (или оставьте пустым): ")
+	fmt.Println("-------------------------------")
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return 0, true
+	}
+	parentID, err := strconv.Atoi(input)
+	if err != nil || parentID < 0 {
+		fmt.Println("🔴 Ошибка: Некорректный ID родительской задачи")
+		fmt.Println("-------------------------------")
+		return 0, false
+	}
+	return parentID, true
+}
+
+// Функция для вывода названия приоритета
+func priorityName(priority int) string {
+	switch priority {
+	case model.PriorityLow:
+		return "низкий"
+	case model.PriorityNormal:
+		return "обычный"
+	case model.PriorityHigh:
+		return "высокий"
+	case model.PriorityCritical:
+		return "критический"
+	}
+	return strconv.Itoa(priority)
+}
+
+// Функция для вывода спринта задачи
+func taskSprint(task model.Task) string {
+	if task.SprintID == 0 {
+		return "вне спринтов"
+	}
+	return strconv.Itoa(task.SprintID)
+}
+
+// Функция для вывода срока выполнения задачи
+func dueDate(task model.Task) string {
+	if task.Due.IsZero() {
+		return "без срока"
+	}
+	return task.Due.Format("02.01.2006")
+}
 
-	fmt.Println("\n✅ Задача успешно обновлена!")
+// Функция для выбора нового статуса задачи
+// Предлагаются только статусы, в которые разрешён переход из текущего.
+// Возвращает пустой статус, если статус менять не нужно.
+func chooseStatus(scanner *bufio.Scanner, storage storage.Interface, task model.Task) (model.Status, bool) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	next, err := storage.NextStatuses(ctx, task.StatusID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении статусов:", err)
+		return model.Status{}, false
+	}
+
+	fmt.Printf("\n🚦 Текущий статус: %s\n", task.Status)
+	if len(next) == 0 {
+		fmt.Println("⚠️  Из этого статуса переходов нет.")
+		return model.Status{}, true
+	}
+	for i, status := range next {
+		fmt.Printf("%d. %s\n", i+1, status.Name)
+	}
+	fmt.Print("\n🚦 Выберите новый статус (Enter - оставить текущий): ")
 	fmt.Println("-------------------------------")
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return model.Status{}, true
+	}
+
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 || n > len(next) {
+		fmt.Println("\n🔴 Ошибка: Некорректный номер статуса")
+		return model.Status{}, false
+	}
+	return next[n-1], true
 }
 
 // Функция для удаления задачи
@@ -264,22 +916,81 @@ func deleteTask(scanner *bufio.Scanner, storage storage.Interface) {
 		return
 	}
 
-	err = storage.DeleteTask(taskID)
+	ctx, cancel := operation()
+	defer cancel()
+
+	err = storage.DeleteTask(ctx, taskID)
+	if err != nil {
+		printError("\n🔴 Ошибка при удалении задачи:", err)
+		return
+	}
+	session.add(step{kind: opDelete, taskID: taskID})
+
+	fmt.Println("\n✅ Задача перемещена в корзину, её можно восстановить в разделе «Корзина»")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для закрытия задачи
+func closeTask(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID задачи для закрытия: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	unblocked, err := storage.CloseTask(ctx, taskID)
+	if err != nil {
+		printError("\n🔴 Ошибка при закрытии задачи:", err)
+		return
+	}
+
+	fmt.Println("\n✅ Задача закрыта!")
+	if len(unblocked) > 0 {
+		fmt.Println("\n🔓 Разблокированы задачи:")
+		for _, task := range unblocked {
+			fmt.Printf("🆔 ID: %d | 📌 Заголовок: %s\n", task.ID, task.Title)
+		}
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для повторного открытия задачи
+func reopenTask(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID задачи для переоткрытия: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err = storage.ReopenTask(ctx, taskID)
 	if err != nil {
-		fmt.Println("\n🔴 Ошибка при удалении задачи:", err)
+		printError("\n🔴 Ошибка при переоткрытии задачи:", err)
 		return
 	}
 
-	fmt.Println("\n✅ Задача успешно удалена!")
+	fmt.Println("\n✅ Задача снова открыта!")
 	fmt.Println("-------------------------------")
 }
 
 // Функция для вывода всех меток
 func printLabels(storage storage.Interface) {
-	labels, err := storage.Labels()
+	ctx, cancel := operation()
+	defer cancel()
+
+	labels, err := storage.Labels(ctx)
 	if err != nil {
 		fmt.Println("-------------------------------")
-		fmt.Println("\n🔴 Ошибка при получении списка меток:", err)
+		printError("\n🔴 Ошибка при получении списка меток:", err)
 		fmt.Println("-------------------------------")
 		return
 	}
@@ -308,13 +1019,16 @@ func createLabel(scanner *bufio.Scanner, storage storage.Interface) {
 
 	name := strings.TrimSpace(scanner.Text())
 
-	label := postgres.Label{
+	label := model.Label{
 		Name: name,
 	}
 
-	id, err := storage.NewLabel(label)
+	ctx, cancel := operation()
+	defer cancel()
+
+	id, err := storage.NewLabel(ctx, label)
 	if err != nil {
-		fmt.Println("\n 🔴 Ошибка при создании метки: ", err)
+		printError("\n 🔴 Ошибка при создании метки:", err)
 		fmt.Println("-------------------------------")
 		return
 	}
@@ -324,12 +1038,131 @@ func createLabel(scanner *bufio.Scanner, storage storage.Interface) {
 
 }
 
+// Функция для добавления метки к задаче
+func attachLabel(scanner *bufio.Scanner, storage storage.Interface) {
+	taskID, labelID, ok := scanTaskLabel(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err := storage.AttachLabel(ctx, taskID, labelID)
+	if err != nil {
+		printError("\n🔴 Ошибка при добавлении метки:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+	session.add(step{kind: opLabel, taskID: taskID, labelID: labelID})
+
+	fmt.Println("\n✅ Метка добавлена к задаче!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для снятия метки с задачи
+func detachLabel(scanner *bufio.Scanner, storage storage.Interface) {
+	taskID, labelID, ok := scanTaskLabel(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err := storage.DetachLabel(ctx, taskID, labelID)
+	if err != nil {
+		printError("\n🔴 Ошибка при снятии метки:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+	session.add(step{kind: opUnlabel, taskID: taskID, labelID: labelID})
+
+	fmt.Println("\n✅ Метка снята с задачи!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для ввода задачи и метки
+func scanTaskLabel(scanner *bufio.Scanner) (int, int, bool) {
+	fmt.Print("\n🆔 Введите ID задачи: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID задачи")
+		return 0, 0, false
+	}
+
+	fmt.Print("\n🏷️  Введите ID метки: ")
+	scanner.Scan()
+	labelID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID метки")
+		return 0, 0, false
+	}
+	return taskID, labelID, true
+}
+
+// Функция для переименования метки
+func renameLabel(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🏷️  Введите ID метки: ")
+	scanner.Scan()
+	labelID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID метки")
+		return
+	}
+
+	fmt.Print("\n🏷️  Введите новое название метки: ")
+	scanner.Scan()
+	name := strings.TrimSpace(scanner.Text())
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err = storage.UpdateLabel(ctx, model.Label{ID: labelID, Name: name})
+	if err != nil {
+		printError("\n🔴 Ошибка при переименовании метки:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Метка переименована!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для удаления метки
+func deleteLabel(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🏷️  Введите ID метки для удаления: ")
+	scanner.Scan()
+	labelID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID метки")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err = storage.DeleteLabel(ctx, labelID)
+	if err != nil {
+		printError("\n🔴 Ошибка при удалении метки:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Метка удалена и снята со всех задач!")
+	fmt.Println("-------------------------------")
+}
+
 // Функция для вывода пользователей
 func printUsers(storage storage.Interface) {
-	users, err := storage.Users()
+	ctx, cancel := operation()
+	defer cancel()
+
+	users, err := storage.Users(ctx)
 	if err != nil {
 		fmt.Println("-------------------------------")
-		fmt.Println("\n🔴 Ошибка при получении списка пользователей:", err)
+		printError("\n🔴 Ошибка при получении списка пользователей:", err)
 		fmt.Println("-------------------------------")
 		return
 	}
@@ -358,13 +1191,16 @@ func createUser(scanner *bufio.Scanner, storage storage.Interface) {
 
 	name := strings.TrimSpace(scanner.Text())
 
-	user := postgres.User{
+	user := model.User{
 		Name: name,
 	}
 
-	id, err := storage.NewUser(user)
+	ctx, cancel := operation()
+	defer cancel()
+
+	id, err := storage.NewUser(ctx, user)
 	if err != nil {
-		fmt.Println("\n🔴 Ошибка при создании пользователя: ", err)
+		printError("\n🔴 Ошибка при создании пользователя:", err)
 		fmt.Println("-------------------------------")
 		return
 	}
@@ -386,9 +1222,12 @@ func getTasksByIdUser(scanner *bufio.Scanner, storage storage.Interface) {
 		return
 	}
 
-	tasks, err := storage.GetTasksByAuthor(authorID)
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.GetTasksByAuthor(ctx, authorID)
 	if err != nil {
-		fmt.Println("\n🔴 Ошибка при получении списка задач:", err)
+		printError("\n🔴 Ошибка при получении списка задач:", err)
 		fmt.Println("-------------------------------")
 		return
 	}
@@ -406,3 +1245,257 @@ func getTasksByIdUser(scanner *bufio.Scanner, storage storage.Interface) {
 	}
 	fmt.Println("-------------------------------")
 }
+
+// Функция для вывода просроченных задач и задач со сроком в ближайшие сутки
+func printOverdueTasks(storage storage.Interface) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.OverdueTasks(ctx, dueSoon)
+	if err != nil {
+		fmt.Println("-------------------------------")
+		printError("\n🔴 Ошибка при получении списка задач:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("-------------------------------")
+		fmt.Println("\n✅ Просроченных и горящих задач нет.")
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	now := time.Now()
+	fmt.Println("\n⏰ Просроченные и горящие задачи:")
+	for _, task := range tasks {
+		mark := "🟡"
+		if task.Due.Before(now) {
+			mark = "🔴"
+		}
+		fmt.Printf("%s 🆔 ID: %d | ⚡ %s | 📅 %s | 📌 %s | 🎯 Исполнитель: %d\n",
+			mark, task.ID, priorityName(task.Priority), dueDate(task), task.Title, task.AssignedID)
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для вывода статусов и разрешённых переходов
+func printStatuses(storage storage.Interface) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	statuses, err := storage.Statuses(ctx)
+	if err != nil {
+		fmt.Println("-------------------------------")
+		printError("\n🔴 Ошибка при получении списка статусов:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("-------------------------------")
+		fmt.Println("\n⚠️  Статусы отсутствуют.")
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("-------------------------------")
+	fmt.Println("\n🚦 Статусы и переходы:")
+	for _, status := range statuses {
+		next, err := storage.NextStatuses(ctx, status.ID)
+		if err != nil {
+			printError("\n🔴 Ошибка при получении переходов:", err)
+			return
+		}
+		names := make([]string, 0, len(next))
+		for _, n := range next {
+			names = append(names, n.Name)
+		}
+		fmt.Printf("ID: %d | %s → %s\n", status.ID, status.Name, strings.Join(names, ", "))
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для создания нового статуса
+func createStatus(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Println("-------------------------------")
+	fmt.Print("\n🚦 Введите название статуса: ")
+	fmt.Println("-------------------------------")
+	scanner.Scan()
+	name := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("\n🔢 Введите позицию статуса в процессе: ")
+	fmt.Println("-------------------------------")
+	scanner.Scan()
+	position, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n🔴 Ошибка: Некорректная позиция")
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	id, err := storage.NewStatus(ctx, model.Status{Name: name, Position: position})
+	if err != nil {
+		printError("\n🔴 Ошибка при создании статуса:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n✅ Статус успешно создан! ID: %d\n", id)
+	fmt.Println("-------------------------------")
+}
+
+// Функция для разрешения перехода между статусами
+func addTransition(scanner *bufio.Scanner, storage storage.Interface) {
+	fromID, toID, ok := scanTransition(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err := storage.AddTransition(ctx, fromID, toID)
+	if err != nil {
+		printError("\n🔴 Ошибка при добавлении перехода:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Переход разрешён!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для запрета перехода между статусами
+func deleteTransition(scanner *bufio.Scanner, storage storage.Interface) {
+	fromID, toID, ok := scanTransition(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err := storage.DeleteTransition(ctx, fromID, toID)
+	if err != nil {
+		printError("\n🔴 Ошибка при удалении перехода:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Переход запрещён!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для ввода пары статусов перехода
+func scanTransition(scanner *bufio.Scanner) (int, int, bool) {
+	fmt.Print("\n🚦 Введите ID исходного статуса: ")
+	scanner.Scan()
+	fromID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n🔴 Ошибка: Некорректный ID статуса")
+		return 0, 0, false
+	}
+
+	fmt.Print("\n🚦 Введите ID нового статуса: ")
+	scanner.Scan()
+	toID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n🔴 Ошибка: Некорректный ID статуса")
+		return 0, 0, false
+	}
+	return fromID, toID, true
+}
+
+// Функция для добавления блокирующей задачи
+func addDependency(scanner *bufio.Scanner, storage storage.Interface) {
+	taskID, blockerID, ok := scanDependency(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err := storage.AddDependency(ctx, taskID, blockerID)
+	if err != nil {
+		printError("\n🔴 Ошибка при добавлении блокировки:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n🔒 Задача %d теперь заблокирована задачей %d\n", taskID, blockerID)
+	fmt.Println("-------------------------------")
+}
+
+// Функция для снятия блокировки задачи
+func removeDependency(scanner *bufio.Scanner, storage storage.Interface) {
+	taskID, blockerID, ok := scanDependency(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	err := storage.RemoveDependency(ctx, taskID, blockerID)
+	if err != nil {
+		printError("\n🔴 Ошибка при снятии блокировки:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n🔓 Блокировка снята!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для ввода заблокированной и блокирующей задачи
+func scanDependency(scanner *bufio.Scanner) (int, int, bool) {
+	fmt.Print("\n🆔 Введите ID заблокированной задачи: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return 0, 0, false
+	}
+
+	fmt.Print("\n🆔 Введите ID блокирующей задачи: ")
+	scanner.Scan()
+	blockerID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return 0, 0, false
+	}
+	return taskID, blockerID, true
+}
+
+// Функция для вывода открытых задач, которые ничем не заблокированы
+func printUnblockedTasks(storage storage.Interface) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.UnblockedTasks(ctx)
+	if err != nil {
+		fmt.Println("-------------------------------")
+		printError("\n🔴 Ошибка при получении списка задач:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("-------------------------------")
+		fmt.Println("\n⚠️  Задач, готовых к работе, нет.")
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n🚀 Задачи, готовые к работе:")
+	for _, task := range tasks {
+		fmt.Printf("🆔 ID: %d | ⚡ %s | 📌 %s | 🎯 Исполнитель: %d\n",
+			task.ID, priorityName(task.Priority), task.Title, task.AssignedID)
+	}
+	fmt.Println("-------------------------------")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage/memdb"
+)
+
+func TestRebaseEdits(t *testing.T) {
+	original := model.Task{ID: 1, Title: "старый", Content: "описание", StatusID: 1, Status: "backlog", Priority: model.PriorityNormal, Version: 1}
+	// Пользователь поменял заголовок и приоритет, статус оставил
+	edited := original
+	edited.Title = "мой"
+	edited.Priority = model.PriorityHigh
+	edited.StatusID, edited.Status = 0, ""
+	// Тем временем другой пользователь поменял описание и статус
+	current := original
+	current.Content = "чужое"
+	current.StatusID, current.Status = 2, "in progress"
+	current.Version = 2
+
+	got := rebaseEdits(original, edited, current)
+	want := current
+	want.Title = "мой"
+	want.Priority = model.PriorityHigh
+	if len(model.Diff(want, got)) != 0 || got.Version != 2 {
+		t.Errorf("rebaseEdits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.New()
+	parent, _ := db.NewTask(ctx, model.Task{Title: "родитель"}, nil)
+	child, _ := db.NewTask(ctx, model.Task{Title: "подзадача", ParentID: parent}, nil)
+	db.NewTask(ctx, model.Task{Title: "вложенная", ParentID: child}, nil)
+	leaf, _ := db.NewTask(ctx, model.Task{Title: "без подзадач"}, nil)
+	if _, err := db.CloseTask(ctx, child); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+
+	tasks := []model.Task{loadTestTask(t, db, parent), loadTestTask(t, db, child), loadTestTask(t, db, leaf)}
+	got, err := subtaskProgress(ctx, db, tasks)
+	if err != nil {
+		t.Fatalf("subtaskProgress() error = %v", err)
+	}
+	want := map[int]string{parent: "1/2 подзадач закрыто", child: "0/1 подзадач закрыто"}
+	if len(got) != len(want) || got[parent] != want[parent] || got[child] != want[child] {
+		t.Errorf("subtaskProgress() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"task-meneger/pkg/storage/postgres"
+)
+
+// Функция для команды migrate: обновление схемы БД без запуска меню
+//
+//	task-meneger migrate up        применить все новые миграции
+//	task-meneger migrate down [N]  откатить N последних миграций (по умолчанию одну)
+//	task-meneger migrate status    список миграций и время их применения
+//
+// Миграции могут выполняться дольше обычной операции, поэтому
+// ограничения DB_TIMEOUT нет, прервать команду можно по Ctrl+C.
+// Возвращает код завершения программы.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("Использование: migrate up | down [N] | status")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cfg, err := migrateConfig()
+	if err != nil {
+		printError("❌ Ошибка подключения к БД:", err)
+		return 1
+	}
+	db, err := postgres.Connect(ctx, cfg)
+	if err != nil {
+		printError("❌ Ошибка подключения к БД:", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		done, err := db.MigrateUp(ctx)
+		for _, m := range done {
+			fmt.Printf("⬆️  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			printError("❌ Ошибка при применении миграций:", err)
+			return 1
+		}
+		if len(done) == 0 {
+			fmt.Println("✅ Схема БД уже актуальна.")
+		} else {
+			fmt.Printf("✅ Применено миграций: %d\n", len(done))
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Println("❌ Ошибка: число миграций должно быть положительным")
+				return 2
+			}
+		}
+		done, err := db.MigrateDown(ctx, steps)
+		for _, m := range done {
+			fmt.Printf("⬇️  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			printError("❌ Ошибка при откате миграций:", err)
+			return 1
+		}
+		fmt.Printf("✅ Откачено миграций: %d\n", len(done))
+	case "status":
+		status, err := db.MigrationStatus(ctx)
+		if err != nil {
+			printError("❌ Ошибка при получении состояния миграций:", err)
+			return 1
+		}
+		for _, m := range status {
+			applied := "⏳ не применена"
+			if !m.Applied.IsZero() {
+				applied = "✅ применена " + m.Applied.Format("02.01.2006 15:04")
+			}
+			fmt.Printf("%04d_%-20s %s\n", m.Version, m.Name, applied)
+		}
+	default:
+		fmt.Printf("❌ Неизвестная команда migrate %s\n", args[0])
+		return 2
+	}
+	return 0
+}
+
+// Функция для настроек БД команды migrate
+// Автоматические миграции отключены: иначе status не показал бы новых миграций,
+// а down перед откатом применил бы их все
+func migrateConfig() (postgres.Config, error) {
+	cfg, err := postgres.LoadConfig(os.Getenv("DB_CONFIG"))
+	if err != nil {
+		return postgres.Config{}, err
+	}
+	cfg.AutoMigrate = false
+	return cfg, nil
+}
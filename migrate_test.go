@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"task-meneger/pkg/storage/postgres"
+)
+
+func TestMigrateConfig_NoAutoMigrate(t *testing.T) {
+	t.Setenv("DB_CONFIG", "")
+	t.Setenv("DATABASE_URL", "postgres://app@db.local/tasks")
+	t.Setenv("DB_AUTO_MIGRATE", "true")
+
+	cfg, err := migrateConfig()
+	if err != nil {
+		t.Fatalf("migrateConfig() error = %v", err)
+	}
+	if cfg.AutoMigrate {
+		t.Error("migrateConfig() AutoMigrate = true, want false")
+	}
+}
+
+// migrate status с DB_AUTO_MIGRATE не применяет новые миграции.
+// Тест откатывает последнюю миграцию, поэтому нужна отдельная тестовая БД
+// из TEST_DATABASE_URL, как в тестах pkg/storage/postgres.
+func TestRunMigrate_StatusKeepsPending(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL не задана, тесты с PostgreSQL пропущены")
+	}
+	ctx := context.Background()
+	db, err := postgres.Connect(ctx, postgres.Config{URL: url})
+	if err != nil {
+		t.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	t.Cleanup(db.Close)
+	if _, err := db.MigrateUp(ctx); err != nil {
+		t.Fatalf("Ошибка применения миграций: %v", err)
+	}
+	if _, err := db.MigrateDown(ctx, 1); err != nil {
+		t.Fatalf("Ошибка отката миграции: %v", err)
+	}
+	t.Cleanup(func() { db.MigrateUp(ctx) })
+
+	t.Setenv("DB_CONFIG", "")
+	t.Setenv("DATABASE_URL", url)
+	t.Setenv("DB_AUTO_MIGRATE", "true")
+	if code := runMigrate([]string{"status"}); code != 0 {
+		t.Fatalf("runMigrate(status) = %d, want 0", code)
+	}
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	if last := status[len(status)-1]; !last.Applied.IsZero() {
+		t.Errorf("после migrate status миграция %04d_%s применена, want не применена", last.Version, last.Name)
+	}
+}
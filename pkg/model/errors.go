@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Ошибки хранилища, общие для всех его реализаций.
+// Реализации оборачивают их в ошибки с подробностями,
+// поэтому проверять их следует через errors.Is.
+var (
+	// ErrNotFound — запись с указанным id не существует.
+	ErrNotFound = errors.New("запись не найдена")
+	// ErrConflict — изменение конфликтует с текущим состоянием записи.
+	ErrConflict = errors.New("конфликт с текущим состоянием записи")
+	// ErrInvalid — переданы некорректные данные.
+	ErrInvalid = errors.New("некорректные данные")
+	// ErrReferenced — нарушена ссылка на связанную запись
+	// (связанная запись не существует или на удаляемую запись ссылаются другие).
+	ErrReferenced = errors.New("нарушена связь с другой записью")
+)
+
+// ErrParentCycle — попытка вложить задачу в саму себя или в свою подзадачу.
+var ErrParentCycle = fmt.Errorf("%w: задача не может быть вложена в саму себя или в свою подзадачу", ErrInvalid)
+
+// ErrDependencyCycle — зависимость между задачами замыкает цикл блокировок.
+var ErrDependencyCycle = fmt.Errorf("%w: зависимость приводит к циклу блокировок", ErrInvalid)
+
+// ErrVersionConflict — задачу изменили после того, как её прочитал вызывающий:
+// версия в обновлении не совпадает с текущей версией задачи.
+var ErrVersionConflict = fmt.Errorf("%w: задача изменена другим пользователем", ErrConflict)
+
+// TransitionError — ошибка недопустимого перехода задачи между статусами.
+// Является ErrConflict: переход запрещён из текущего статуса задачи.
+type TransitionError struct {
+	TaskID int
+	FromID int
+	ToID   int
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("недопустимый переход задачи %d из статуса %d в статус %d",
+		e.TaskID, e.FromID, e.ToID)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrConflict
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// Запись истории изменений задачи.
+// Изменение полей задачи записывается отдельной записью на каждое поле.
+type Change struct {
+	ID     int
+	TaskID int
+	Actor  User      // кто изменил задачу, имя заполняется при чтении
+	Time   time.Time // когда
+	Action string    // одна из констант Action*
+	Field  string    // изменённое поле, одна из констант Field*, только для ActionUpdate
+	Before string    // значение до изменения, для меток - название снятой метки
+	After  string    // значение после изменения, для меток - название добавленной метки
+}
+
+// Действия с задачей в истории.
+const (
+	ActionCreate  = "create"  // задача создана, After - заголовок
+	ActionUpdate  = "update"  // изменено поле Field
+	ActionDelete  = "delete"  // задача перемещена в корзину, Before - заголовок
+	ActionRestore = "restore" // задача восстановлена из корзины
+	ActionPurge   = "purge"   // задача удалена из корзины навсегда, Before - заголовок
+	ActionClose   = "close"   // задача закрыта
+	ActionReopen  = "reopen"  // задача переоткрыта
+	ActionLabel   = "label"   // добавлена метка
+	ActionUnlabel = "unlabel" // снята метка
+)
+
+// Поля задачи в истории.
+const (
+	FieldTitle    = "title"
+	FieldContent  = "content"
+	FieldAuthor   = "author"
+	FieldAssignee = "assignee"
+	FieldStatus   = "status"
+	FieldPriority = "priority"
+	FieldDue      = "due"
+	FieldParent   = "parent"
+	FieldProject  = "project"
+	FieldSprint   = "sprint"
+)
+
+// Формат срока выполнения в истории.
+const dueLayout = "2006-01-02 15:04"
+
+// Diff возвращает изменения полей задачи при переходе от before к after.
+// Статус сравнивается по названию, поэтому Status должен быть заполнен.
+func Diff(before, after Task) []Change {
+	var changes []Change
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, Change{
+				TaskID: after.ID,
+				Action: ActionUpdate,
+				Field:  field,
+				Before: from,
+				After:  to,
+			})
+		}
+	}
+	add(FieldTitle, before.Title, after.Title)
+	add(FieldContent, before.Content, after.Content)
+	add(FieldAuthor, strconv.Itoa(before.AuthorID), strconv.Itoa(after.AuthorID))
+	add(FieldAssignee, strconv.Itoa(before.AssignedID), strconv.Itoa(after.AssignedID))
+	add(FieldStatus, before.Status, after.Status)
+	add(FieldPriority, strconv.Itoa(before.Priority), strconv.Itoa(after.Priority))
+	add(FieldDue, dueValue(before.Due), dueValue(after.Due))
+	add(FieldParent, strconv.Itoa(before.ParentID), strconv.Itoa(after.ParentID))
+	add(FieldProject, strconv.Itoa(before.ProjectID), strconv.Itoa(after.ProjectID))
+	add(FieldSprint, strconv.Itoa(before.SprintID), strconv.Itoa(after.SprintID))
+	return changes
+}
+
+// dueValue возвращает срок выполнения для истории, пустой - без срока.
+func dueValue(due time.Time) string {
+	if due.IsZero() {
+		return ""
+	}
+	return due.Format(dueLayout)
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	before := Task{ID: 7, Title: "старый", AssignedID: 1, Status: "backlog", ProjectID: 1}
+	after := before
+	after.Title = "новый"
+	after.AssignedID = 2
+	after.Due = time.Date(2026, 1, 2, 23, 59, 0, 0, time.Local)
+	after.ProjectID = 3
+
+	changes := Diff(before, after)
+	want := []Change{
+		{TaskID: 7, Action: ActionUpdate, Field: FieldTitle, Before: "старый", After: "новый"},
+		{TaskID: 7, Action: ActionUpdate, Field: FieldAssignee, Before: "1", After: "2"},
+		{TaskID: 7, Action: ActionUpdate, Field: FieldDue, Before: "", After: "2026-01-02 23:59"},
+		{TaskID: 7, Action: ActionUpdate, Field: FieldProject, Before: "1", After: "3"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("Diff()[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("Diff() без изменений = %+v, want пусто", changes)
+	}
+}
@@ -0,0 +1,141 @@
+// Пакет model содержит предметную модель трекера задач,
+// общую для всех реализаций хранилища.
+package model
+
+import "time"
+
+// Задача.
+type Task struct {
+	ID         int
+	Opened     time.Time // время создания задачи
+	Closed     time.Time // время выполнения, нулевое - задача открыта
+	AuthorID   int
+	AssignedID int
+	StatusID   int       // 0 - статус по умолчанию при создании или текущий при обновлении
+	Status     string    // название статуса, заполняется при чтении
+	Priority   int       // приоритет, одна из констант Priority*
+	Due        time.Time // срок выполнения, нулевое - без срока
+	ParentID   int       // родительская задача, 0 - задача верхнего уровня
+	Title      string
+	Content    string
+	Labels     []Label   // метки задачи, заполняются при чтении
+	Version    int       // версия задачи, увеличивается при каждом изменении
+	Deleted    time.Time // время перемещения в корзину, нулевое - задача не удалена
+	ProjectID  int       // 0 - проект из контекста при создании или текущий при обновлении
+	Rank       string    // ранг задачи в колонке доски, см. пакет rank
+	SprintID   int       // спринт задачи, 0 - вне спринтов; меняется только через AddToSprint/RemoveFromSprint
+}
+
+// IsDeleted сообщает, находится ли задача в корзине.
+func (t Task) IsDeleted() bool {
+	return !t.Deleted.IsZero()
+}
+
+// IsClosed сообщает, выполнена ли задача.
+func (t Task) IsClosed() bool {
+	return !t.Closed.IsZero()
+}
+
+// Приоритеты задач.
+const (
+	PriorityLow = iota
+	PriorityNormal
+	PriorityHigh
+	PriorityCritical
+)
+
+// Состояния задачи для фильтрации.
+const (
+	StateAll = iota
+	StateOpen
+	StateClosed
+)
+
+// Фильтр задач. Нулевые значения полей выборку не ограничивают.
+type TaskFilter struct {
+	TaskID     int
+	AuthorID   int
+	AssignedID int
+	LabelsAny  []int     // задача отмечена хотя бы одной из меток
+	LabelsAll  []int     // задача отмечена всеми метками
+	OpenedFrom time.Time // границы времени создания, включительно
+	OpenedTo   time.Time
+	ClosedFrom time.Time // границы времени выполнения, включительно
+	ClosedTo   time.Time
+	SprintID   int  // задачи спринта
+	State      int  // одна из констант State*
+	Trash      bool // только задачи в корзине, иначе задачи в корзине не выбираются
+
+	Sort   string // поле сортировки, одна из констант Sort*, по умолчанию SortID
+	Desc   bool   // сортировка по убыванию
+	Limit  int    // размер страницы, 0 - без ограничения
+	Offset int    // число пропускаемых задач
+	After  int    // keyset-пагинация: задачи после задачи с этим id
+	Before int    // keyset-пагинация: задачи перед задачей с этим id
+}
+
+// Поля сортировки задач.
+const (
+	SortID     = "id"
+	SortOpened = "opened"
+	SortClosed = "closed"
+	SortTitle  = "title"
+)
+
+// Результат полнотекстового поиска задач.
+type SearchResult struct {
+	Task    Task
+	Rank    float64 // релевантность, чем больше - тем выше в выдаче
+	Snippet string  // фрагмент текста, совпадения выделены «ёлочками»
+}
+
+// Метка.
+type Label struct {
+	ID   int
+	Name string
+}
+
+// Пользователь.
+type User struct {
+	ID   int
+	Name string
+}
+
+// Комментарий к задаче.
+type Comment struct {
+	ID      int
+	TaskID  int
+	Author  User
+	Created time.Time
+	Edited  time.Time // время последнего редактирования, нулевое - не редактировался
+	Content string
+}
+
+// Статус задачи.
+type Status struct {
+	ID       int
+	Name     string
+	Position int // порядок статуса в процессе работы
+}
+
+// Колонка канбан-доски: статус и его задачи в порядке рангов.
+type Column struct {
+	Status Status
+	Tasks  []Task
+}
+
+// Разрешённый переход между статусами.
+type Transition struct {
+	FromID int
+	ToID   int
+}
+
+// Проект, в котором ведутся задачи.
+type Project struct {
+	ID          int
+	Name        string
+	Description string
+}
+
+// DefaultProject — проект, в который попадают задачи, если проект не указан.
+const DefaultProject = 1
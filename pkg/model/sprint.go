@@ -0,0 +1,107 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Спринт: отрезок времени, на который планируются задачи проекта.
+type Sprint struct {
+	ID        int
+	ProjectID int // 0 - проект из контекста при создании
+	Name      string
+	Start     time.Time
+	End       time.Time
+}
+
+// Итоги спринта, вычисленные по истории задач.
+type SprintSummary struct {
+	Sprint    Sprint
+	Committed int // задачи, запланированные к началу спринта
+	Added     int // задачи, добавленные после начала спринта
+	Removed   int // задачи, убранные из спринта после его начала
+	Completed int // задачи спринта, закрытые к концу спринта
+	Remaining int // задачи спринта, не закрытые к концу спринта
+}
+
+// SummarizeSprint подводит итоги спринта по истории задач changes.
+// Незавершённый спринт оценивается на момент now, ещё не начавшийся - тоже:
+// все запланированные задачи считаются взятыми в спринт.
+// Задача в корзине в спринт не входит.
+func SummarizeSprint(sprint Sprint, changes []Change, now time.Time) SprintSummary {
+	end := sprint.End
+	if now.Before(end) {
+		end = now
+	}
+	start := sprint.Start
+	if end.Before(start) {
+		start = end
+	}
+
+	byTask := make(map[int][]Change)
+	for _, c := range changes {
+		byTask[c.TaskID] = append(byTask[c.TaskID], c)
+	}
+
+	summary := SprintSummary{Sprint: sprint}
+	id := strconv.Itoa(sprint.ID)
+	for _, history := range byTask {
+		sort.SliceStable(history, func(i, j int) bool {
+			if !history[i].Time.Equal(history[j].Time) {
+				return history[i].Time.Before(history[j].Time)
+			}
+			return history[i].ID < history[j].ID
+		})
+
+		// Состояние задачи по мере воспроизведения истории
+		var planned, closed, deleted bool
+		inSprint := func() bool { return planned && !deleted }
+		var atStart, ever bool
+		started := false
+		for _, c := range history {
+			if c.Time.After(end) {
+				break
+			}
+			if !started && c.Time.After(start) {
+				atStart, started = inSprint(), true
+			}
+			switch {
+			case c.Action == ActionUpdate && c.Field == FieldSprint:
+				planned = c.After == id
+			case c.Action == ActionClose:
+				closed = true
+			case c.Action == ActionReopen:
+				closed = false
+			case c.Action == ActionDelete, c.Action == ActionPurge:
+				deleted = true
+			case c.Action == ActionRestore:
+				deleted = false
+			}
+			if started && inSprint() {
+				ever = true
+			}
+		}
+		if !started {
+			atStart = inSprint()
+		}
+
+		switch {
+		case atStart:
+			summary.Committed++
+		case ever:
+			summary.Added++
+		default:
+			continue
+		}
+		switch {
+		case !inSprint():
+			summary.Removed++
+		case closed:
+			summary.Completed++
+		default:
+			summary.Remaining++
+		}
+	}
+	return summary
+}
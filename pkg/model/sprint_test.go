@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSummarizeSprint(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	sprint := Sprint{ID: 5, Name: "март", Start: start, End: start.AddDate(0, 0, 14)}
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	plan := func(task, n int, from, to string) Change {
+		return Change{TaskID: task, Time: day(n), Action: ActionUpdate, Field: FieldSprint, Before: from, After: to}
+	}
+	changes := []Change{
+		// 1: запланирована до начала и закрыта
+		plan(1, -1, "0", "5"),
+		{TaskID: 1, Time: day(3), Action: ActionClose},
+		// 2: запланирована до начала, не закрыта
+		plan(2, -2, "0", "5"),
+		// 3: добавлена в середине спринта и закрыта
+		plan(3, 4, "0", "5"),
+		{TaskID: 3, Time: day(6), Action: ActionClose},
+		// 4: запланирована до начала, убрана в середине
+		plan(4, -1, "0", "5"),
+		plan(4, 5, "5", "0"),
+		// 5: добавлена и удалена в корзину
+		plan(5, 2, "0", "5"),
+		{TaskID: 5, Time: day(3), Action: ActionDelete},
+		// 6: закрыта после окончания спринта
+		plan(6, -1, "0", "5"),
+		{TaskID: 6, Time: day(20), Action: ActionClose},
+		// 7: перенесена в следующий спринт до его начала
+		plan(7, -3, "0", "5"),
+		plan(7, -2, "5", "6"),
+		// 8: добавлена после окончания спринта
+		plan(8, 15, "0", "5"),
+	}
+
+	got := SummarizeSprint(sprint, changes, day(30))
+	want := SprintSummary{Sprint: sprint, Committed: 4, Added: 2, Removed: 2, Completed: 2, Remaining: 2}
+	if got != want {
+		t.Errorf("SummarizeSprint() = %+v, want %+v", got, want)
+	}
+
+	// Идущий спринт оценивается на текущий момент
+	got = SummarizeSprint(sprint, changes, day(4))
+	want = SprintSummary{Sprint: sprint, Committed: 4, Added: 2, Removed: 1, Completed: 1, Remaining: 4}
+	if got != want {
+		t.Errorf("SummarizeSprint() в середине спринта = %+v, want %+v", got, want)
+	}
+
+	// До начала спринта все запланированные задачи считаются взятыми в спринт
+	got = SummarizeSprint(sprint, changes, day(-1))
+	want = SprintSummary{Sprint: sprint, Committed: 4, Remaining: 4}
+	if got != want {
+		t.Errorf("SummarizeSprint() до начала = %+v, want %+v", got, want)
+	}
+}
+
+func TestSprintValidate(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	valid := Sprint{Name: "март", Start: start, End: start.AddDate(0, 0, 14)}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for name, s := range map[string]Sprint{
+		"без названия": {Start: valid.Start, End: valid.End},
+		"без дат":      {Name: "март"},
+		"конец раньше": {Name: "март", Start: valid.End, End: valid.Start},
+	} {
+		if err := s.Validate(); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate() %s: error = %v, want ErrInvalid", name, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+// Узел дерева задач.
+type TaskNode struct {
+	Task     Task
+	Children []*TaskNode
+}
+
+// Progress возвращает число закрытых подзадач и общее число подзадач
+// на всех уровнях вложенности.
+func (n *TaskNode) Progress() (closed, total int) {
+	for _, child := range n.Children {
+		if child.Task.IsClosed() {
+			closed++
+		}
+		c, t := child.Progress()
+		closed += c
+		total += t + 1
+	}
+	return closed, total
+}
+
+// BuildTree строит дерево задач по ParentID.
+// Задачи, родитель которых отсутствует в списке, становятся корнями.
+// Порядок задач внутри одного уровня сохраняется.
+func BuildTree(tasks []Task) []*TaskNode {
+	nodes := make(map[int]*TaskNode, len(tasks))
+	for _, t := range tasks {
+		nodes[t.ID] = &TaskNode{Task: t}
+	}
+
+	var roots []*TaskNode
+	for _, t := range tasks {
+		node := nodes[t.ID]
+		parent, ok := nodes[t.ParentID]
+		if t.ParentID == 0 || !ok || parent == node {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots
+}
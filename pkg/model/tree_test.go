@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildTree(t *testing.T) {
+	done := time.Unix(1, 0)
+	tasks := []Task{
+		{ID: 1},
+		{ID: 2, ParentID: 1, Closed: done},
+		{ID: 3, ParentID: 1},
+		{ID: 4, ParentID: 3, Closed: done},
+		{ID: 5, ParentID: 42}, // родителя нет в списке
+	}
+
+	roots := BuildTree(tasks)
+	if len(roots) != 2 || roots[0].Task.ID != 1 || roots[1].Task.ID != 5 {
+		t.Fatalf("BuildTree() корни = %v, want [1 5]", roots)
+	}
+	if len(roots[0].Children) != 2 {
+		t.Fatalf("BuildTree() подзадач у 1 = %d, want 2", len(roots[0].Children))
+	}
+
+	closed, total := roots[0].Progress()
+	if closed != 2 || total != 3 {
+		t.Errorf("TaskNode.Progress() = %d/%d, want 2/3", closed, total)
+	}
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate проверяет задачу перед сохранением.
+// Ссылки на другие записи (автор, статус, родитель) проверяет хранилище.
+func (t Task) Validate() error {
+	switch {
+	case strings.TrimSpace(t.Title) == "":
+		return fmt.Errorf("%w: у задачи должен быть заголовок", ErrInvalid)
+	case t.Priority < PriorityLow || t.Priority > PriorityCritical:
+		return fmt.Errorf("%w: неизвестный приоритет %d", ErrInvalid, t.Priority)
+	case t.ID != 0 && t.ParentID == t.ID:
+		return ErrParentCycle
+	}
+	return nil
+}
+
+// Validate проверяет фильтр задач.
+func (f TaskFilter) Validate() error {
+	switch f.Sort {
+	case "", SortID, SortOpened, SortClosed, SortTitle:
+	default:
+		return fmt.Errorf("%w: неизвестное поле сортировки %q", ErrInvalid, f.Sort)
+	}
+	switch {
+	case f.State < StateAll || f.State > StateClosed:
+		return fmt.Errorf("%w: неизвестное состояние задач %d", ErrInvalid, f.State)
+	case f.Limit < 0 || f.Offset < 0:
+		return fmt.Errorf("%w: размер страницы и отступ не могут быть отрицательными", ErrInvalid)
+	case f.After != 0 && f.Before != 0:
+		return fmt.Errorf("%w: нельзя задать одновременно After и Before", ErrInvalid)
+	case !f.OpenedTo.IsZero() && f.OpenedTo.Before(f.OpenedFrom),
+		!f.ClosedTo.IsZero() && f.ClosedTo.Before(f.ClosedFrom):
+		return fmt.Errorf("%w: начало периода позже его конца", ErrInvalid)
+	}
+	return nil
+}
+
+// Validate проверяет метку перед сохранением.
+func (l Label) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return fmt.Errorf("%w: у метки должно быть название", ErrInvalid)
+	}
+	return nil
+}
+
+// Validate проверяет пользователя перед сохранением.
+func (u User) Validate() error {
+	if strings.TrimSpace(u.Name) == "" {
+		return fmt.Errorf("%w: у пользователя должно быть имя", ErrInvalid)
+	}
+	return nil
+}
+
+// Validate проверяет комментарий перед сохранением.
+func (c Comment) Validate() error {
+	if strings.TrimSpace(c.Content) == "" {
+		return fmt.Errorf("%w: комментарий не может быть пустым", ErrInvalid)
+	}
+	return nil
+}
+
+// Validate проверяет проект перед сохранением.
+func (p Project) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: у проекта должно быть название", ErrInvalid)
+	}
+	return nil
+}
+
+// Validate проверяет спринт перед сохранением.
+func (s Sprint) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Name) == "":
+		return fmt.Errorf("%w: у спринта должно быть название", ErrInvalid)
+	case s.Start.IsZero() || s.End.IsZero():
+		return fmt.Errorf("%w: у спринта должны быть даты начала и окончания", ErrInvalid)
+	case !s.End.After(s.Start):
+		return fmt.Errorf("%w: спринт должен заканчиваться позже начала", ErrInvalid)
+	}
+	return nil
+}
+
+// Validate проверяет статус перед сохранением.
+func (s Status) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: у статуса должно быть название", ErrInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Пакет rank строит лексикографические ранги для упорядочивания записей:
+// чтобы поставить запись между двумя соседними, достаточно выдать ей ранг
+// между их рангами, остальные записи не меняются.
+//
+// Ранг - непустая строка из цифр и строчных латинских букв, не оканчивающаяся
+// на "0". Ранги сравниваются побайтно, в postgres - с COLLATE "C".
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Цифры рангов в порядке возрастания и средняя из них.
+const (
+	digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	middle = "i"
+)
+
+// Минимальная длина ранга, который выдаётся после последнего:
+// запас на последующие ранги без удлинения строки.
+const width = 5
+
+// ErrInvalid — некорректный ранг или границы не по возрастанию.
+var ErrInvalid = errors.New("некорректный ранг")
+
+// Between возвращает ранг строго между a и b.
+// Пустой a - начало списка, пустой b - конец списка.
+func Between(a, b string) (string, error) {
+	for _, r := range []string{a, b} {
+		if !valid(r) {
+			return "", fmt.Errorf("%w: %q", ErrInvalid, r)
+		}
+	}
+	if b == "" {
+		return after(a), nil
+	}
+	if a >= b {
+		return "", fmt.Errorf("%w: %q не меньше %q", ErrInvalid, a, b)
+	}
+
+	var result []byte
+	// bounded - результат пока совпадает с началом b, b ограничивает цифру сверху
+	bounded := true
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(a) {
+			lo = strings.IndexByte(digits, a[i])
+		}
+		hi := len(digits)
+		if bounded {
+			hi = strings.IndexByte(digits, b[i])
+		}
+		if hi-lo > 1 {
+			return string(append(result, digits[(lo+hi)/2])), nil
+		}
+		result = append(result, digits[lo])
+		if lo < hi {
+			bounded = false
+		}
+	}
+}
+
+// after возвращает ранг больше a: a, дополненный нулями до width,
+// увеличивается на единицу как число. Так подряд добавляемые в конец
+// записи получают короткие ранги, а не удлиняют их с каждым разом.
+func after(a string) string {
+	if a == "" {
+		a = middle
+	}
+	b := []byte(a + strings.Repeat("0", max(0, width-len(a))))
+	for i := len(b) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, b[i])
+		if d+1 < len(digits) {
+			b[i] = digits[d+1]
+			// Обнулённые переносом младшие цифры отбрасываются:
+			// ранг не оканчивается на "0", иначе перед ним может не найтись места
+			return string(b[:i+1])
+		}
+		b[i] = '0'
+	}
+	// Все цифры максимальные - ранг удлиняется
+	return a + middle
+}
+
+// valid сообщает, можно ли использовать строку как ранг или границу.
+func valid(r string) bool {
+	if strings.HasSuffix(r, "0") {
+		return false
+	}
+	for i := 0; i < len(r); i++ {
+		if strings.IndexByte(digits, r[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package rank
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "i0001"},
+		{"i0001", "", "i0002"},
+		{"i000z", "", "i001"},
+		{"zzzzz", "", "zzzzzi"},
+		{"", "i0001", "9"},
+		{"", "1", "0i"},
+		{"i0001", "i0002", "i0001i"},
+		{"a", "c", "b"},
+		{"a", "b", "ai"},
+		{"az", "b", "azi"},
+		{"1", "10i", "109"},
+	}
+	for _, tt := range tests {
+		got, err := Between(tt.a, tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("Between(%q, %q) = %q, %v, want %q", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestBetween_Errors(t *testing.T) {
+	for _, tt := range [][2]string{{"b", "a"}, {"a", "a"}, {"A", ""}, {"", "a0"}} {
+		if _, err := Between(tt[0], tt[1]); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Between(%q, %q) error = %v, want ErrInvalid", tt[0], tt[1], err)
+		}
+	}
+}
+
+// Случайные вставки между соседями сохраняют порядок рангов
+func TestBetween_Order(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 2000; i++ {
+		pos := rnd.Intn(len(ranks) + 1)
+		var a, b string
+		if pos > 0 {
+			a = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			b = ranks[pos]
+		}
+		r, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) error = %v", a, b, err)
+		}
+		if r <= a || (b != "" && r >= b) {
+			t.Fatalf("Between(%q, %q) = %q вне границ", a, b, r)
+		}
+		ranks = append(ranks[:pos], append([]string{r}, ranks[pos:]...)...)
+	}
+	if !sort.StringsAreSorted(ranks) {
+		t.Errorf("ранги не упорядочены: %v", ranks)
+	}
+
+	// Добавление в конец не удлиняет ранги
+	last := ""
+	for i := 0; i < 10000; i++ {
+		last, _ = Between(last, "")
+	}
+	if len(last) > width {
+		t.Errorf("ранг после 10000 добавлений в конец = %q, want не длиннее %d", last, width)
+	}
+}
@@ -0,0 +1,18 @@
+package storage
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor возвращает контекст, изменения в котором выполняются
+// от имени пользователя userID. Хранилища записывают его в историю задач.
+func WithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// Actor возвращает пользователя, от имени которого выполняются изменения,
+// по умолчанию - пользователь 0.
+func Actor(ctx context.Context) int {
+	id, _ := ctx.Value(actorKey{}).(int)
+	return id
+}
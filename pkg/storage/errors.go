@@ -0,0 +1,13 @@
+package storage
+
+import "task-meneger/pkg/model"
+
+// Ошибки, которые возвращают все реализации Interface.
+// Проверять их следует через errors.Is: реализации оборачивают
+// их в ошибки с подробностями.
+var (
+	ErrNotFound   = model.ErrNotFound   // запись не найдена
+	ErrConflict   = model.ErrConflict   // конфликт с текущим состоянием записи
+	ErrInvalid    = model.ErrInvalid    // некорректные данные
+	ErrReferenced = model.ErrReferenced // нарушена связь с другой записью
+)
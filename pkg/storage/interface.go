@@ -1,21 +1,79 @@
 package storage
 
-import "task-meneger/pkg/storage/postgres"
+import (
+	"context"
+	"time"
+
+	"task-meneger/pkg/model"
+)
 
 //Интерфес БД
 type Interface interface {
 	//Tasks
-	Tasks(int, int) ([]postgres.Task, error)
-	NewTask(postgres.Task, []int) (int, error)
-	UpdateTask(postgres.Task) error
-	DeleteTask(int) error
+	Tasks(context.Context, model.TaskFilter) ([]model.Task, error)
+	NewTask(context.Context, model.Task, []int) (int, error)
+	UpdateTask(context.Context, model.Task) error
+	DeleteTask(context.Context, int) error
+	CloseTask(context.Context, int) ([]model.Task, error)
+	ReopenTask(context.Context, int) error
+	//Trash
+	RestoreTask(context.Context, int) error
+	PurgeTasks(context.Context, time.Time) (int, error)
+	//Subtasks
+	Subtasks(context.Context, int) ([]model.Task, error)
+	TaskTree(context.Context, int) ([]*model.TaskNode, error)
+	//Board
+	Board(context.Context) ([]model.Column, error)
+	MoveTask(context.Context, int, int, int) error
 	//Labels
-	Labels() ([]postgres.Label, error)
-	NewLabel(postgres.Label) (int, error)
+	Labels(context.Context) ([]model.Label, error)
+	NewLabel(context.Context, model.Label) (int, error)
+	UpdateLabel(context.Context, model.Label) error
+	DeleteLabel(context.Context, int) error
+	AttachLabel(context.Context, int, int) error
+	DetachLabel(context.Context, int, int) error
+	//Dependencies
+	AddDependency(context.Context, int, int) error
+	RemoveDependency(context.Context, int, int) error
+	Blockers(context.Context, int) ([]model.Task, error)
+	UnblockedTasks(context.Context) ([]model.Task, error)
+	//Comments
+	Comments(context.Context, int) ([]model.Comment, error)
+	NewComment(context.Context, model.Comment) (int, error)
+	UpdateComment(context.Context, model.Comment) error
+	DeleteComment(context.Context, int) error
+	//Statuses
+	Statuses(context.Context) ([]model.Status, error)
+	NewStatus(context.Context, model.Status) (int, error)
+	Transitions(context.Context) ([]model.Transition, error)
+	AddTransition(context.Context, int, int) error
+	DeleteTransition(context.Context, int, int) error
+	NextStatuses(context.Context, int) ([]model.Status, error)
 	//Users
-	Users() ([]postgres.User, error)
-	NewUser(postgres.User) (int, error)
+	Users(context.Context) ([]model.User, error)
+	NewUser(context.Context, model.User) (int, error)
+	//Projects
+	Projects(context.Context) ([]model.Project, error)
+	NewProject(context.Context, model.Project) (int, error)
+	UpdateProject(context.Context, model.Project) error
+	DeleteProject(context.Context, int) error
+	ProjectMembers(context.Context, int) ([]model.User, error)
+	AddProjectMember(context.Context, int, int) error
+	RemoveProjectMember(context.Context, int, int) error
+	//Sprints
+	Sprints(context.Context) ([]model.Sprint, error)
+	NewSprint(context.Context, model.Sprint) (int, error)
+	AddToSprint(context.Context, int, int) error
+	RemoveFromSprint(context.Context, int, int) error
+	CarryOver(context.Context, int, int) (int, error)
+	SprintSummary(context.Context, int) (model.SprintSummary, error)
+	//History
+	History(context.Context, int) ([]model.Change, error)
+	//Transactions
+	WithTx(context.Context, func(Interface) error) error // всё или ничего: ошибка fn отменяет изменения, сделанные через переданное хранилище
 	//Search
-	GetTasksByAuthor(int) ([]postgres.Task, error)
+	GetTasksByAuthor(context.Context, int) ([]model.Task, error)
+	OverdueTasks(context.Context, time.Duration) ([]model.Task, error)
+	SearchTasks(context.Context, string, int) ([]model.SearchResult, error)
 	Close() // для закрытия соединения с БД
 }
@@ -0,0 +1,111 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/rank"
+	"task-meneger/pkg/storage"
+)
+
+// Board — Канбан-доска проекта из контекста: колонки по статусам, задачи по рангам
+func (db *DB) Board(ctx context.Context) ([]model.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	project := storage.Project(ctx)
+	statuses := db.sortedStatuses()
+	columns := make([]model.Column, len(statuses))
+	for i, st := range statuses {
+		columns[i].Status = st
+		var tasks []model.Task
+		for _, t := range db.tasks {
+			if t.StatusID == st.ID && !t.IsDeleted() && inProject(t, project) {
+				tasks = append(tasks, t)
+			}
+		}
+		sort.SliceStable(tasks, func(i, j int) bool {
+			if tasks[i].Rank != tasks[j].Rank {
+				return tasks[i].Rank < tasks[j].Rank
+			}
+			return tasks[i].ID < tasks[j].ID
+		})
+		columns[i].Tasks = db.withLabels(tasks)
+	}
+	return columns, nil
+}
+
+// MoveTask — Перемещение задачи в колонку статуса statusID сразу после задачи afterID,
+// при afterID = 0 - в начало колонки, при statusID = 0 - внутри своей колонки.
+// Задача afterID должна быть из того же проекта
+func (db *DB) MoveTask(ctx context.Context, taskID, statusID, afterID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	i := db.activeIndex(taskID)
+	if i < 0 {
+		return fmt.Errorf("задача %d: %w", taskID, model.ErrNotFound)
+	}
+	t := db.tasks[i]
+	if statusID == 0 {
+		statusID = t.StatusID
+	}
+	if statusID != t.StatusID && !db.allowed(t.StatusID, statusID) {
+		return &model.TransitionError{TaskID: taskID, FromID: t.StatusID, ToID: statusID}
+	}
+
+	// Соседи задачи на новом месте: prev - задача afterID, next - следующая за ней
+	var prev, next string
+	if afterID != 0 {
+		j := db.activeIndex(afterID)
+		if j < 0 {
+			return fmt.Errorf("задача %d: %w", afterID, model.ErrNotFound)
+		}
+		if afterID == taskID || db.tasks[j].StatusID != statusID {
+			return fmt.Errorf("%w: задача %d не стоит в колонке статуса %d", model.ErrInvalid, afterID, statusID)
+		}
+		if db.tasks[j].ProjectID != t.ProjectID {
+			return fmt.Errorf("%w: задача %d из другого проекта, чем задача %d", model.ErrInvalid, afterID, taskID)
+		}
+		prev = db.tasks[j].Rank
+	}
+	for _, other := range db.tasks {
+		if other.StatusID == statusID && !other.IsDeleted() && other.ID != taskID && other.ProjectID == t.ProjectID &&
+			other.Rank > prev && (next == "" || other.Rank < next) {
+			next = other.Rank
+		}
+	}
+	r, err := rank.Between(prev, next)
+	if err != nil {
+		return fmt.Errorf("ошибка при вычислении ранга задачи %d: %w", taskID, err)
+	}
+
+	t.StatusID = statusID
+	t.Status = db.statusName(statusID)
+	t.Rank = r
+	if err := db.record(ctx, model.Diff(db.tasks[i], t)...); err != nil {
+		return err
+	}
+	t.Version++
+	db.tasks[i] = t
+	return nil
+}
+
+// lastRank — Ранг после рангов всех задач проекта: задача с ним встаёт в конец любой колонки доски проекта
+func (db *DB) lastRank(projectID int) (string, error) {
+	var last string
+	for _, t := range db.tasks {
+		if t.ProjectID == projectID && t.Rank > last {
+			last = t.Rank
+		}
+	}
+	r, err := rank.Between(last, "")
+	if err != nil {
+		return "", fmt.Errorf("ошибка при вычислении ранга задачи: %w", err)
+	}
+	return r, nil
+}
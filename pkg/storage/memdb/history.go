@@ -0,0 +1,48 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// History — История изменений задачи в хронологическом порядке,
+// история удалённой задачи сохраняется, как и в postgres
+func (db *DB) History(ctx context.Context, taskID int) ([]model.Change, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	var result []model.Change
+	for _, c := range db.history {
+		if c.TaskID == taskID {
+			for _, u := range db.users {
+				if u.ID == c.Actor.ID {
+					c.Actor.Name = u.Name
+				}
+			}
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// record — Запись изменений задач в историю от имени пользователя из ctx.
+// Вызывается последней из проверок перед изменением данных:
+// если пользователя нет, изменение не выполняется
+func (db *DB) record(ctx context.Context, changes ...model.Change) error {
+	actor := storage.Actor(ctx)
+	if !db.hasUser(actor) {
+		return fmt.Errorf("пользователь %d: %w", actor, model.ErrReferenced)
+	}
+	for _, c := range changes {
+		c.ID = db.nextChange
+		db.nextChange++
+		c.Actor = model.User{ID: actor}
+		c.Time = now()
+		db.history = append(db.history, c)
+	}
+	return nil
+}
@@ -1,86 +1,1048 @@
 package memdb
 
-import "task-meneger/pkg/storage/postgres"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Зависимость: задача taskID заблокирована задачей blockerID.
+type dependency struct {
+	taskID    int
+	blockerID int
+}
+
+// Связь задачи с меткой.
+type taskLabel struct {
+	taskID  int
+	labelID int
+}
+
+// DB — хранилище в памяти, реализация storage.Interface для тестов и отладки.
+// Безопасно для одновременного использования из нескольких горутин.
 type DB struct {
-	tasks  []postgres.Task
-	labels []postgres.Label
-	users  []postgres.User
-	nextID int
+	mu     sync.RWMutex
+	locked bool // БД - транзакция внутри WithTx, блокировка уже захвачена
+	data
 }
 
+// data — Содержимое БД, которое транзакция копирует и подменяет целиком
+type data struct {
+	tasks        []model.Task
+	taskLabels   []taskLabel
+	dependencies []dependency
+	labels       []model.Label
+	users        []model.User
+	statuses     []model.Status
+	transitions  []model.Transition
+	comments     []model.Comment
+	history      []model.Change
+	projects     []model.Project
+	members      []projectMember
+	sprints      []model.Sprint
+
+	// Счётчики id, как последовательности SERIAL в postgres:
+	// id удалённых записей повторно не выдаются
+	nextTask    int
+	nextLabel   int
+	nextUser    int
+	nextStatus  int
+	nextComment int
+	nextChange  int
+	nextProject int
+	nextSprint  int
+}
+
+var _ storage.Interface = (*DB)(nil)
+
 func New() *DB {
-	return &DB{nextID: 1}
+	return &DB{data: data{
+		nextTask:    1,
+		nextLabel:   1,
+		nextUser:    1,
+		nextStatus:  5,
+		nextComment: 1,
+		nextChange:  1,
+		nextProject: model.DefaultProject + 1,
+		nextSprint:  1,
+		// Пользователь по умолчанию, как в начальной миграции postgres
+		users: []model.User{{ID: 0, Name: "default"}},
+		// Процесс работы по умолчанию, как в начальной миграции postgres
+		statuses: []model.Status{
+			{ID: 1, Name: "backlog", Position: 1},
+			{ID: 2, Name: "in progress", Position: 2},
+			{ID: 3, Name: "review", Position: 3},
+			{ID: 4, Name: "done", Position: 4},
+		},
+		transitions: []model.Transition{
+			{FromID: 1, ToID: 2}, {FromID: 2, ToID: 1},
+			{FromID: 2, ToID: 3}, {FromID: 3, ToID: 2},
+			{FromID: 3, ToID: 4}, {FromID: 4, ToID: 2},
+		},
+		// Проект по умолчанию, как в миграции postgres
+		projects: []model.Project{{ID: model.DefaultProject, Name: "Общий"}},
+	}}
 }
 
-// Tasks — Получение списка задач
-func (db *DB) Tasks(int, int) ([]postgres.Task, error) {
-	return db.tasks, nil
+// Tasks — Получение списка задач по фильтру с сортировкой и пагинацией
+func (db *DB) Tasks(ctx context.Context, f model.TaskFilter) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	key := sortKeys[f.Sort]
+	if f.Sort == "" {
+		key = sortKeys[model.SortID]
+	}
+	// less — порядок задач по полю сортировки, при равенстве - по id
+	less := func(a, b model.Task) bool {
+		c := key(a, b)
+		if c == 0 {
+			c = compareInt64(int64(a.ID), int64(b.ID))
+		}
+		return c != 0 && (c < 0) != f.Desc
+	}
+
+	var cursor *model.Task
+	for i, t := range db.tasks {
+		if (f.After != 0 && t.ID == f.After) || (f.Before != 0 && t.ID == f.Before) {
+			cursor = &db.tasks[i]
+		}
+	}
+
+	project := storage.Project(ctx)
+	var result []model.Task
+	for _, t := range db.tasks {
+		if !inProject(t, project) || !db.matches(t, f) {
+			continue
+		}
+		if f.After != 0 && (cursor == nil || !less(*cursor, t)) {
+			continue
+		}
+		if f.Before != 0 && (cursor == nil || !less(t, *cursor)) {
+			continue
+		}
+		result = append(result, t)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return less(result[i], result[j])
+	})
+
+	// Для Before отступ и размер страницы отсчитываются от курсора назад
+	start, end := f.Offset, len(result)
+	if f.Before != 0 {
+		start, end = 0, len(result)-f.Offset
+	}
+	if start > len(result) || end < 0 {
+		return nil, nil
+	}
+	if f.Limit > 0 && end-start > f.Limit {
+		if f.Before != 0 {
+			start = end - f.Limit
+		} else {
+			end = start + f.Limit
+		}
+	}
+	return db.withLabels(result[start:end]), nil
+}
+
+// sortKeys — Сравнение задач по полям сортировки
+var sortKeys = map[string]func(a, b model.Task) int{
+	model.SortID: func(a, b model.Task) int {
+		return compareInt64(int64(a.ID), int64(b.ID))
+	},
+	model.SortOpened: func(a, b model.Task) int {
+		return a.Opened.Compare(b.Opened)
+	},
+	model.SortClosed: func(a, b model.Task) int {
+		return a.Closed.Compare(b.Closed)
+	},
+	model.SortTitle: func(a, b model.Task) int {
+		return strings.Compare(a.Title, b.Title)
+	},
+}
+
+// now — Текущее время с точностью до секунды, как оно хранится в postgres
+func now() time.Time {
+	return time.Now().Truncate(time.Second)
 }
 
-// NewTask — Создание новой задачи
-func (db *DB) NewTask(task postgres.Task, labels []int) (int, error) {
-	task.ID = db.nextID
-	db.nextID++
+// compareInt64 — Сравнение чисел: -1, 0 или 1
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// matches — Проверка задачи на соответствие фильтру
+func (db *DB) matches(t model.Task, f model.TaskFilter) bool {
+	switch {
+	case f.TaskID != 0 && t.ID != f.TaskID,
+		f.AuthorID != 0 && t.AuthorID != f.AuthorID,
+		f.AssignedID != 0 && t.AssignedID != f.AssignedID,
+		f.SprintID != 0 && t.SprintID != f.SprintID,
+		!f.OpenedFrom.IsZero() && t.Opened.Before(f.OpenedFrom),
+		!f.OpenedTo.IsZero() && t.Opened.After(f.OpenedTo),
+		!f.ClosedFrom.IsZero() && t.Closed.Before(f.ClosedFrom),
+		!f.ClosedTo.IsZero() && (!t.IsClosed() || t.Closed.After(f.ClosedTo)),
+		f.State == model.StateOpen && t.IsClosed(),
+		f.State == model.StateClosed && !t.IsClosed(),
+		t.IsDeleted() != f.Trash:
+		return false
+	}
+
+	if len(f.LabelsAny) > 0 {
+		found := false
+		for _, labelID := range f.LabelsAny {
+			if db.hasLabel(t.ID, labelID) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, labelID := range f.LabelsAll {
+		if !db.hasLabel(t.ID, labelID) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLabel — Проверка, отмечена ли задача меткой
+func (db *DB) hasLabel(taskID, labelID int) bool {
+	for _, l := range db.taskLabels {
+		if l.taskID == taskID && l.labelID == labelID {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTask — Создание новой задачи с метками, при ошибке задача не создаётся
+func (db *DB) NewTask(ctx context.Context, task model.Task, labels []int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := task.Validate(); err != nil {
+		return 0, err
+	}
+	var id int
+	err := db.withTx(ctx, func(tx *DB) error {
+		var err error
+		id, err = tx.newTask(ctx, task, labels)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// newTask — Создание задачи и связей с метками внутри транзакции
+func (db *DB) newTask(ctx context.Context, task model.Task, labels []int) (int, error) {
+	task.ProjectID = storage.TaskProject(ctx, task)
+	if err := db.checkReferences(task); err != nil {
+		return 0, err
+	}
+	task.ID = db.nextTask
+	db.nextTask++
+	task.Opened = now()
+	task.Closed = time.Time{}
+	task.Deleted = time.Time{}
+	task.SprintID = 0
+	task.Version = 1
+	if task.StatusID == 0 && len(db.statuses) > 0 {
+		task.StatusID = db.firstStatus().ID
+	}
+	task.Status = db.statusName(task.StatusID)
+	task.Labels = nil
+	// Новая задача встаёт в конец своей колонки доски
+	r, err := db.lastRank(task.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	task.Rank = r
+	if err := db.record(ctx, model.Change{TaskID: task.ID, Action: model.ActionCreate, After: task.Title}); err != nil {
+		return 0, err
+	}
 	db.tasks = append(db.tasks, task)
+	for _, labelID := range labels {
+		if err := db.AttachLabel(ctx, task.ID, labelID); err != nil {
+			return 0, err
+		}
+	}
 	return task.ID, nil
 }
 
 // UpdateTask — Обновление задачи
-func (db *DB) UpdateTask(updatedTask postgres.Task) error {
-	for i, t := range db.tasks {
-		if t.ID == updatedTask.ID {
-			db.tasks[i] = updatedTask
-			return nil
+func (db *DB) UpdateTask(ctx context.Context, updatedTask model.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if err := updatedTask.Validate(); err != nil {
+		return err
+	}
+	if updatedTask.ParentID != 0 && db.isAncestor(updatedTask.ID, updatedTask.ParentID) {
+		return model.ErrParentCycle
+	}
+	i := db.activeIndex(updatedTask.ID)
+	if i < 0 {
+		return fmt.Errorf("задача %d: %w", updatedTask.ID, model.ErrNotFound)
+	}
+	t := db.tasks[i]
+	if updatedTask.Version != t.Version {
+		return fmt.Errorf("задача %d: версия %d, текущая %d: %w", t.ID, updatedTask.Version, t.Version, model.ErrVersionConflict)
+	}
+	if updatedTask.StatusID != 0 && updatedTask.StatusID != t.StatusID {
+		if !db.allowed(t.StatusID, updatedTask.StatusID) {
+			return &model.TransitionError{TaskID: t.ID, FromID: t.StatusID, ToID: updatedTask.StatusID}
+		}
+		// При смене статуса задача встаёт в конец новой колонки доски
+		project := t.ProjectID
+		if updatedTask.ProjectID != 0 {
+			project = updatedTask.ProjectID
+		}
+		r, err := db.lastRank(project)
+		if err != nil {
+			return err
 		}
+		t.StatusID = updatedTask.StatusID
+		t.Status = db.statusName(t.StatusID)
+		t.Rank = r
 	}
-	return nil // Можно вернуть ошибку, если задача не найдена
+	// Время создания и закрытия не меняются при обновлении, как и в postgres
+	t.Title = updatedTask.Title
+	t.Content = updatedTask.Content
+	t.AuthorID = updatedTask.AuthorID
+	t.AssignedID = updatedTask.AssignedID
+	t.Priority = updatedTask.Priority
+	t.Due = updatedTask.Due
+	t.ParentID = updatedTask.ParentID
+	// Спринты у каждого проекта свои: при переносе задача убирается из спринта
+	if updatedTask.ProjectID != 0 && updatedTask.ProjectID != t.ProjectID {
+		t.ProjectID = updatedTask.ProjectID
+		t.SprintID = 0
+	}
+	if err := db.checkReferences(t); err != nil {
+		return err
+	}
+	if err := db.record(ctx, model.Diff(db.tasks[i], t)...); err != nil {
+		return err
+	}
+	t.Version++
+	db.tasks[i] = t
+	return nil
 }
 
-// DeleteTask — Удаление задачи
-func (db *DB) DeleteTask(id int) error {
+// taskIndex — Индекс задачи в срезе по id, -1 если задачи нет
+func (db *DB) taskIndex(id int) int {
 	for i, t := range db.tasks {
 		if t.ID == id {
-			db.tasks = append(db.tasks[:i], db.tasks[i+1:]...)
+			return i
+		}
+	}
+	return -1
+}
+
+// activeIndex — Индекс задачи не из корзины, -1 если такой задачи нет
+func (db *DB) activeIndex(id int) int {
+	i := db.taskIndex(id)
+	if i < 0 || db.tasks[i].IsDeleted() {
+		return -1
+	}
+	return i
+}
+
+// checkReferences — Проверка ссылок задачи на другие записи, как внешние ключи в postgres
+func (db *DB) checkReferences(t model.Task) error {
+	switch {
+	case !db.hasUser(t.AuthorID):
+		return fmt.Errorf("автор %d: %w", t.AuthorID, model.ErrReferenced)
+	case !db.hasUser(t.AssignedID):
+		return fmt.Errorf("исполнитель %d: %w", t.AssignedID, model.ErrReferenced)
+	case t.ParentID != 0 && db.taskIndex(t.ParentID) < 0:
+		return fmt.Errorf("родительская задача %d: %w", t.ParentID, model.ErrReferenced)
+	case t.StatusID != 0 && db.statusName(t.StatusID) == "":
+		return fmt.Errorf("статус %d: %w", t.StatusID, model.ErrReferenced)
+	case db.projectIndex(t.ProjectID) < 0:
+		return fmt.Errorf("проект %d: %w", t.ProjectID, model.ErrReferenced)
+	}
+	return nil
+}
+
+// hasUser — Проверка существования пользователя
+func (db *DB) hasUser(id int) bool {
+	for _, u := range db.users {
+		if u.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteTask — Перемещение задачи в корзину
+func (db *DB) DeleteTask(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	i := db.activeIndex(id)
+	if i < 0 {
+		return fmt.Errorf("задача %d: %w", id, model.ErrNotFound)
+	}
+	if err := db.record(ctx, model.Change{TaskID: id, Action: model.ActionDelete, Before: db.tasks[i].Title}); err != nil {
+		return err
+	}
+	db.tasks[i].Deleted = now()
+	db.tasks[i].Version++
+	return nil
+}
+
+// CloseTask — Закрытие задачи, возвращает задачи, которые стали незаблокированными
+func (db *DB) CloseTask(ctx context.Context, id int) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.lock()()
+	i := db.activeIndex(id)
+	if i < 0 {
+		return nil, fmt.Errorf("задача %d: %w", id, model.ErrNotFound)
+	}
+	if !db.tasks[i].IsClosed() {
+		if err := db.record(ctx, model.Change{TaskID: id, Action: model.ActionClose}); err != nil {
+			return nil, err
+		}
+		db.tasks[i].Closed = now()
+		db.tasks[i].Version++
+	}
+
+	var result []model.Task
+	for _, t := range db.tasks {
+		if !t.IsClosed() && !t.IsDeleted() && db.blockedBy(t.ID, id) && !db.isBlocked(t.ID) {
+			result = append(result, t)
+		}
+	}
+	return db.withLabels(result), nil
+}
+
+// ReopenTask — Повторное открытие задачи
+func (db *DB) ReopenTask(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	i := db.activeIndex(id)
+	if i < 0 {
+		return fmt.Errorf("задача %d: %w", id, model.ErrNotFound)
+	}
+	if db.tasks[i].IsClosed() {
+		if err := db.record(ctx, model.Change{TaskID: id, Action: model.ActionReopen}); err != nil {
+			return err
+		}
+		db.tasks[i].Closed = time.Time{}
+		db.tasks[i].Version++
+	}
+	return nil
+}
+
+// Subtasks — Прямые подзадачи задачи
+func (db *DB) Subtasks(ctx context.Context, parentID int) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	var result []model.Task
+	for _, t := range db.tasks {
+		if t.ParentID == parentID && parentID != 0 && !t.IsDeleted() {
+			result = append(result, t)
+		}
+	}
+	return db.withLabels(result), nil
+}
+
+// TaskTree — Дерево задачи с подзадачами, при rootID = 0 - все задачи
+func (db *DB) TaskTree(ctx context.Context, rootID int) ([]*model.TaskNode, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	// Задачи в корзине и в других проектах в дерево не входят,
+	// BuildTree делает их подзадачи корнями
+	project := storage.Project(ctx)
+	var result []model.Task
+	for _, t := range db.tasks {
+		if !t.IsDeleted() && inProject(t, project) && (rootID == 0 || db.inTree(rootID, t.ID, project)) {
+			result = append(result, t)
+		}
+	}
+	return model.BuildTree(db.withLabels(result)), nil
+}
+
+// isAncestor — Проверка, что ancestorID - сама задача id или один из её предков
+func (db *DB) isAncestor(ancestorID, id int) bool {
+	seen := make(map[int]bool)
+	for id != 0 && !seen[id] {
+		if id == ancestorID {
+			return true
+		}
+		seen[id] = true
+		id = db.parentOf(id)
+	}
+	return false
+}
+
+// inTree — Проверка, что задача id входит в дерево задачи rootID:
+// путь от неё до rootID не проходит через задачи в корзине и в других проектах
+func (db *DB) inTree(rootID, id, project int) bool {
+	seen := make(map[int]bool)
+	for id != 0 && !seen[id] {
+		i := db.taskIndex(id)
+		if i < 0 || db.tasks[i].IsDeleted() || !inProject(db.tasks[i], project) {
+			return false
+		}
+		if id == rootID {
+			return true
+		}
+		seen[id] = true
+		id = db.tasks[i].ParentID
+	}
+	return false
+}
+
+// parentOf — Родительская задача по id
+func (db *DB) parentOf(id int) int {
+	for _, t := range db.tasks {
+		if t.ID == id {
+			return t.ParentID
+		}
+	}
+	return 0
+}
+
+// AddDependency — Блокировка задачи taskID задачей blockerID
+func (db *DB) AddDependency(ctx context.Context, taskID, blockerID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if db.dependsOn(blockerID, taskID) {
+		return model.ErrDependencyCycle
+	}
+	for _, id := range []int{taskID, blockerID} {
+		if db.taskIndex(id) < 0 {
+			return fmt.Errorf("задача %d: %w", id, model.ErrReferenced)
+		}
+	}
+	if !db.blockedBy(taskID, blockerID) {
+		db.dependencies = append(db.dependencies, dependency{taskID: taskID, blockerID: blockerID})
+	}
+	return nil
+}
+
+// RemoveDependency — Снятие блокировки задачи taskID задачей blockerID
+func (db *DB) RemoveDependency(ctx context.Context, taskID, blockerID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for i, d := range db.dependencies {
+		if d.taskID == taskID && d.blockerID == blockerID {
+			db.dependencies = append(db.dependencies[:i], db.dependencies[i+1:]...)
 			return nil
 		}
 	}
-	return nil // Можно вернуть ошибку, если задача не найдена
+	return fmt.Errorf("зависимость задачи %d от задачи %d: %w", taskID, blockerID, model.ErrNotFound)
+}
+
+// Blockers — Задачи, которыми заблокирована задача
+func (db *DB) Blockers(ctx context.Context, taskID int) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	var result []model.Task
+	for _, t := range db.tasks {
+		if !t.IsDeleted() && db.blockedBy(taskID, t.ID) {
+			result = append(result, t)
+		}
+	}
+	return db.withLabels(result), nil
+}
+
+// UnblockedTasks — Открытые задачи без открытых блокирующих задач
+func (db *DB) UnblockedTasks(ctx context.Context) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	project := storage.Project(ctx)
+	var result []model.Task
+	for _, t := range db.tasks {
+		if !t.IsClosed() && !t.IsDeleted() && inProject(t, project) && !db.isBlocked(t.ID) {
+			result = append(result, t)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority > result[j].Priority
+	})
+	return db.withLabels(result), nil
+}
+
+// blockedBy — Проверка прямой зависимости задачи taskID от blockerID
+func (db *DB) blockedBy(taskID, blockerID int) bool {
+	for _, d := range db.dependencies {
+		if d.taskID == taskID && d.blockerID == blockerID {
+			return true
+		}
+	}
+	return false
+}
+
+// dependsOn — Проверка, что задача id - это blockerID или заблокирована им через цепочку зависимостей
+func (db *DB) dependsOn(id, blockerID int) bool {
+	seen := make(map[int]bool)
+	queue := []int{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == blockerID {
+			return true
+		}
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		for _, d := range db.dependencies {
+			if d.taskID == cur {
+				queue = append(queue, d.blockerID)
+			}
+		}
+	}
+	return false
+}
+
+// isBlocked — Проверка, есть ли у задачи открытые блокирующие задачи
+func (db *DB) isBlocked(taskID int) bool {
+	for _, t := range db.tasks {
+		if !t.IsClosed() && !t.IsDeleted() && db.blockedBy(taskID, t.ID) {
+			return true
+		}
+	}
+	return false
 }
 
 // Labels — Получение всех меток
-func (db *DB) Labels() ([]postgres.Label, error) {
-	return db.labels, nil
+func (db *DB) Labels(ctx context.Context) ([]model.Label, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	return append([]model.Label(nil), db.labels...), nil
 }
 
 // NewLabel — Добавление новой метки
-func (db *DB) NewLabel(label postgres.Label) (int, error) {
-	label.ID = len(db.labels) + 1
+func (db *DB) NewLabel(ctx context.Context, label model.Label) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	if err := label.Validate(); err != nil {
+		return 0, err
+	}
+	label.ID = db.nextLabel
+	db.nextLabel++
 	db.labels = append(db.labels, label)
 	return label.ID, nil
 }
 
+// Comments — Комментарии к задаче в хронологическом порядке
+func (db *DB) Comments(ctx context.Context, taskID int) ([]model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	var result []model.Comment
+	for _, c := range db.comments {
+		if c.TaskID == taskID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// NewComment — Добавление комментария к задаче
+func (db *DB) NewComment(ctx context.Context, comment model.Comment) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	if err := comment.Validate(); err != nil {
+		return 0, err
+	}
+	if db.taskIndex(comment.TaskID) < 0 {
+		return 0, fmt.Errorf("задача %d: %w", comment.TaskID, model.ErrReferenced)
+	}
+	if !db.hasUser(comment.Author.ID) {
+		return 0, fmt.Errorf("автор %d: %w", comment.Author.ID, model.ErrReferenced)
+	}
+	comment.ID = db.nextComment
+	db.nextComment++
+	comment.Created = now()
+	comment.Edited = time.Time{}
+	for _, u := range db.users {
+		if u.ID == comment.Author.ID {
+			comment.Author.Name = u.Name
+		}
+	}
+	db.comments = append(db.comments, comment)
+	return comment.ID, nil
+}
+
+// UpdateComment — Изменение текста комментария
+func (db *DB) UpdateComment(ctx context.Context, comment model.Comment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if err := comment.Validate(); err != nil {
+		return err
+	}
+	for i, c := range db.comments {
+		if c.ID == comment.ID {
+			db.comments[i].Content = comment.Content
+			db.comments[i].Edited = now()
+			return nil
+		}
+	}
+	return fmt.Errorf("комментарий %d: %w", comment.ID, model.ErrNotFound)
+}
+
+// DeleteComment — Удаление комментария
+func (db *DB) DeleteComment(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for i, c := range db.comments {
+		if c.ID == id {
+			db.comments = append(db.comments[:i], db.comments[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("комментарий %d: %w", id, model.ErrNotFound)
+}
+
+// Statuses — Получение статусов в порядке процесса работы
+func (db *DB) Statuses(ctx context.Context) ([]model.Status, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	return db.sortedStatuses(), nil
+}
+
+// sortedStatuses — Статусы в порядке position
+func (db *DB) sortedStatuses() []model.Status {
+	statuses := append([]model.Status(nil), db.statuses...)
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Position < statuses[j].Position
+	})
+	return statuses
+}
+
+// NewStatus — Добавление нового статуса
+func (db *DB) NewStatus(ctx context.Context, status model.Status) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	if err := status.Validate(); err != nil {
+		return 0, err
+	}
+	status.ID = db.nextStatus
+	db.nextStatus++
+	db.statuses = append(db.statuses, status)
+	return status.ID, nil
+}
+
+// Transitions — Получение разрешённых переходов
+func (db *DB) Transitions(ctx context.Context) ([]model.Transition, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	return append([]model.Transition(nil), db.transitions...), nil
+}
+
+// AddTransition — Разрешение перехода между статусами
+func (db *DB) AddTransition(ctx context.Context, fromID, toID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for _, id := range []int{fromID, toID} {
+		if db.statusName(id) == "" {
+			return fmt.Errorf("статус %d: %w", id, model.ErrReferenced)
+		}
+	}
+	if !db.allowed(fromID, toID) {
+		db.transitions = append(db.transitions, model.Transition{FromID: fromID, ToID: toID})
+	}
+	return nil
+}
+
+// DeleteTransition — Запрет перехода между статусами
+func (db *DB) DeleteTransition(ctx context.Context, fromID, toID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for i, tr := range db.transitions {
+		if tr.FromID == fromID && tr.ToID == toID {
+			db.transitions = append(db.transitions[:i], db.transitions[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("переход из статуса %d в статус %d: %w", fromID, toID, model.ErrNotFound)
+}
+
+// NextStatuses — Статусы, доступные для перехода из данного
+func (db *DB) NextStatuses(ctx context.Context, statusID int) ([]model.Status, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	statuses := db.sortedStatuses()
+	var result []model.Status
+	for _, s := range statuses {
+		if db.allowed(statusID, s.ID) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// allowed — Проверка, разрешён ли переход между статусами
+func (db *DB) allowed(fromID, toID int) bool {
+	for _, tr := range db.transitions {
+		if tr.FromID == fromID && tr.ToID == toID {
+			return true
+		}
+	}
+	return false
+}
+
+// firstStatus — Первый статус процесса, назначается новым задачам
+func (db *DB) firstStatus() model.Status {
+	return db.sortedStatuses()[0]
+}
+
+// statusName — Название статуса по id
+func (db *DB) statusName(id int) string {
+	for _, s := range db.statuses {
+		if s.ID == id {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// UpdateLabel — Переименование метки
+func (db *DB) UpdateLabel(ctx context.Context, label model.Label) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if err := label.Validate(); err != nil {
+		return err
+	}
+	for i, l := range db.labels {
+		if l.ID == label.ID {
+			db.labels[i].Name = label.Name
+			return nil
+		}
+	}
+	return fmt.Errorf("метка %d: %w", label.ID, model.ErrNotFound)
+}
+
+// DeleteLabel — Удаление метки вместе со связями с задачами
+func (db *DB) DeleteLabel(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for i, l := range db.labels {
+		if l.ID == id {
+			var changes []model.Change
+			for _, link := range db.taskLabels {
+				if link.labelID == id {
+					changes = append(changes, model.Change{TaskID: link.taskID, Action: model.ActionUnlabel, Before: l.Name})
+				}
+			}
+			if err := db.record(ctx, changes...); err != nil {
+				return err
+			}
+			db.labels = append(db.labels[:i], db.labels[i+1:]...)
+
+			var links []taskLabel
+			for _, l := range db.taskLabels {
+				if l.labelID != id {
+					links = append(links, l)
+				}
+			}
+			db.taskLabels = links
+			return nil
+		}
+	}
+	return fmt.Errorf("метка %d: %w", id, model.ErrNotFound)
+}
+
+// AttachLabel — Добавление метки к задаче
+func (db *DB) AttachLabel(ctx context.Context, taskID, labelID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if db.hasLabel(taskID, labelID) {
+		return nil
+	}
+	if db.taskIndex(taskID) < 0 {
+		return fmt.Errorf("задача %d: %w", taskID, model.ErrReferenced)
+	}
+	name := db.labelName(labelID)
+	if name == "" {
+		return fmt.Errorf("метка %d: %w", labelID, model.ErrReferenced)
+	}
+	if err := db.record(ctx, model.Change{TaskID: taskID, Action: model.ActionLabel, After: name}); err != nil {
+		return err
+	}
+	db.taskLabels = append(db.taskLabels, taskLabel{taskID: taskID, labelID: labelID})
+	return nil
+}
+
+// DetachLabel — Снятие метки с задачи
+func (db *DB) DetachLabel(ctx context.Context, taskID, labelID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for i, l := range db.taskLabels {
+		if l.taskID == taskID && l.labelID == labelID {
+			if err := db.record(ctx, model.Change{TaskID: taskID, Action: model.ActionUnlabel, Before: db.labelName(labelID)}); err != nil {
+				return err
+			}
+			db.taskLabels = append(db.taskLabels[:i], db.taskLabels[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("метка %d у задачи %d: %w", labelID, taskID, model.ErrNotFound)
+}
+
+// labelName — Название метки по id, пустое если метки нет
+func (db *DB) labelName(id int) string {
+	for _, l := range db.labels {
+		if l.ID == id {
+			return l.Name
+		}
+	}
+	return ""
+}
+
+// withLabels — Копии задач с заполненными метками, отсортированными по id
+func (db *DB) withLabels(tasks []model.Task) []model.Task {
+	var result []model.Task
+	for _, t := range tasks {
+		t.Labels = nil
+		for _, label := range db.labels {
+			for _, l := range db.taskLabels {
+				if l.taskID == t.ID && l.labelID == label.ID {
+					t.Labels = append(t.Labels, label)
+				}
+			}
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
 // Users — Получение всех пользователей
-func (db *DB) Users() ([]postgres.User, error) {
-	return db.users, nil
+func (db *DB) Users(ctx context.Context) ([]model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	return append([]model.User(nil), db.users...), nil
 }
 
 // NewUser — Создание нового пользователя
-func (db *DB) NewUser(user postgres.User) (int, error) {
-	user.ID = len(db.users) + 1
+func (db *DB) NewUser(ctx context.Context, user model.User) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	if err := user.Validate(); err != nil {
+		return 0, err
+	}
+	user.ID = db.nextUser
+	db.nextUser++
 	db.users = append(db.users, user)
 	return user.ID, nil
 }
 
 // GetTasksByAuthor — Получение задач по автору
-func (db *DB) GetTasksByAuthor(authorID int) ([]postgres.Task, error) {
-	var result []postgres.Task
+func (db *DB) GetTasksByAuthor(ctx context.Context, authorID int) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	project := storage.Project(ctx)
+	var result []model.Task
 	for _, t := range db.tasks {
-		if t.AuthorID == authorID {
+		if t.AuthorID == authorID && !t.IsDeleted() && inProject(t, project) {
 			result = append(result, t)
 		}
 	}
-	return result, nil
+	return db.withLabels(result), nil
+}
+
+// OverdueTasks — Открытые задачи со сроком, истёкшим или истекающим в ближайшие soon
+func (db *DB) OverdueTasks(ctx context.Context, soon time.Duration) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	deadline := time.Now().Add(soon)
+	project := storage.Project(ctx)
+	var result []model.Task
+	for _, t := range db.tasks {
+		if !t.IsClosed() && !t.IsDeleted() && inProject(t, project) && !t.Due.IsZero() && !t.Due.After(deadline) {
+			result = append(result, t)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority > result[j].Priority
+		}
+		return result[i].Due.Before(result[j].Due)
+	})
+	return db.withLabels(result), nil
 }
 
 // Close — Закрытие "БД"
@@ -0,0 +1,87 @@
+package memdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+	"task-meneger/pkg/storage/storagetest"
+)
+
+func TestDB_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	labelID, err := db.NewLabel(ctx, model.Label{Name: "bug"})
+	if err != nil {
+		t.Fatalf("NewLabel() error = %v", err)
+	}
+
+	const workers = 8
+	const perWorker = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				task := model.Task{Title: fmt.Sprintf("задача %d-%d", w, i)}
+				if _, err := db.NewTask(ctx, task, []int{labelID}); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := db.Tasks(ctx, model.TaskFilter{LabelsAll: []int{labelID}, Limit: 5}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("ошибка при параллельной работе: %v", err)
+	}
+
+	tasks, err := db.Tasks(ctx, model.TaskFilter{})
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if len(tasks) != workers*perWorker {
+		t.Fatalf("Tasks() вернул %d задач, want %d", len(tasks), workers*perWorker)
+	}
+	seen := make(map[int]bool)
+	for _, task := range tasks {
+		if seen[task.ID] {
+			t.Fatalf("id %d выдан дважды", task.ID)
+		}
+		seen[task.ID] = true
+		if len(task.Labels) != 1 {
+			t.Fatalf("у задачи %d меток %d, want 1", task.ID, len(task.Labels))
+		}
+	}
+}
+
+func TestDB_IDsNotReused(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	first, _ := db.NewLabel(ctx, model.Label{Name: "a"})
+	if err := db.DeleteLabel(ctx, first); err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+	second, _ := db.NewLabel(ctx, model.Label{Name: "b"})
+	if second == first {
+		t.Errorf("NewLabel() повторно выдал id %d удалённой метки", first)
+	}
+	if err := db.DeleteLabel(ctx, first); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("DeleteLabel() удалённой метки error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDB(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Interface { return New() })
+}
@@ -0,0 +1,163 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+)
+
+// Участие пользователя в проекте.
+type projectMember struct {
+	projectID int
+	userID    int
+}
+
+// inProject — Проверка, что задача из проекта project, 0 - из любого проекта
+func inProject(t model.Task, project int) bool {
+	return project == 0 || t.ProjectID == project
+}
+
+// Projects — Получение всех проектов
+func (db *DB) Projects(ctx context.Context) ([]model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	return append([]model.Project(nil), db.projects...), nil
+}
+
+// NewProject — Создание нового проекта
+func (db *DB) NewProject(ctx context.Context, project model.Project) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	if err := project.Validate(); err != nil {
+		return 0, err
+	}
+	project.ID = db.nextProject
+	db.nextProject++
+	db.projects = append(db.projects, project)
+	return project.ID, nil
+}
+
+// UpdateProject — Изменение названия и описания проекта
+func (db *DB) UpdateProject(ctx context.Context, project model.Project) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if err := project.Validate(); err != nil {
+		return err
+	}
+	i := db.projectIndex(project.ID)
+	if i < 0 {
+		return fmt.Errorf("проект %d: %w", project.ID, model.ErrNotFound)
+	}
+	db.projects[i] = project
+	return nil
+}
+
+// DeleteProject — Удаление проекта без задач и спринтов, проект по умолчанию удалить нельзя
+func (db *DB) DeleteProject(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	if id == model.DefaultProject {
+		return fmt.Errorf("проект %d - проект по умолчанию: %w", id, model.ErrConflict)
+	}
+	i := db.projectIndex(id)
+	if i < 0 {
+		return fmt.Errorf("проект %d: %w", id, model.ErrNotFound)
+	}
+	// Задачи, в том числе в корзине, ссылаются на проект, как внешний ключ в postgres
+	for _, t := range db.tasks {
+		if t.ProjectID == id {
+			return fmt.Errorf("проект %d: в нём есть задача %d: %w", id, t.ID, model.ErrReferenced)
+		}
+	}
+	for _, s := range db.sprints {
+		if s.ProjectID == id {
+			return fmt.Errorf("проект %d: в нём есть спринт %d: %w", id, s.ID, model.ErrReferenced)
+		}
+	}
+	db.projects = append(db.projects[:i], db.projects[i+1:]...)
+	var members []projectMember
+	for _, m := range db.members {
+		if m.projectID != id {
+			members = append(members, m)
+		}
+	}
+	db.members = members
+	return nil
+}
+
+// ProjectMembers — Участники проекта
+func (db *DB) ProjectMembers(ctx context.Context, projectID int) ([]model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	var result []model.User
+	for _, u := range db.users {
+		if db.isMember(projectID, u.ID) {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+// AddProjectMember — Добавление пользователя в участники проекта
+func (db *DB) AddProjectMember(ctx context.Context, projectID, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	switch {
+	case db.projectIndex(projectID) < 0:
+		return fmt.Errorf("проект %d: %w", projectID, model.ErrReferenced)
+	case !db.hasUser(userID):
+		return fmt.Errorf("пользователь %d: %w", userID, model.ErrReferenced)
+	}
+	if !db.isMember(projectID, userID) {
+		db.members = append(db.members, projectMember{projectID: projectID, userID: userID})
+	}
+	return nil
+}
+
+// RemoveProjectMember — Исключение пользователя из участников проекта
+func (db *DB) RemoveProjectMember(ctx context.Context, projectID, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	for i, m := range db.members {
+		if m.projectID == projectID && m.userID == userID {
+			db.members = append(db.members[:i], db.members[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("пользователь %d в проекте %d: %w", userID, projectID, model.ErrNotFound)
+}
+
+// projectIndex — Индекс проекта в срезе по id, -1 если проекта нет
+func (db *DB) projectIndex(id int) int {
+	for i, p := range db.projects {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// isMember — Проверка участия пользователя в проекте
+func (db *DB) isMember(projectID, userID int) bool {
+	for _, m := range db.members {
+		if m.projectID == projectID && m.userID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package memdb
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// SearchTasks — Упрощённый полнотекстовый поиск без морфологии:
+// текст разбивается на слова, каждое слово запроса должно быть началом
+// какого-либо слова задачи. Совпадения в названии весят больше, чем в тексте.
+func (db *DB) SearchTasks(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	project := storage.Project(ctx)
+	var results []model.SearchResult
+	for _, t := range db.withLabels(db.tasks) {
+		if t.IsDeleted() || !inProject(t, project) {
+			continue
+		}
+		title, content := tokenize(t.Title), tokenize(t.Content)
+		var rank float64
+		found := true
+		for _, term := range terms {
+			hits := float64(countPrefix(title, term)) + 0.4*float64(countPrefix(content, term))
+			if hits == 0 {
+				found = false
+				break
+			}
+			rank += hits
+		}
+		if !found {
+			continue
+		}
+		results = append(results, model.SearchResult{
+			Task:    t,
+			Rank:    rank / float64(len(title)+len(content)),
+			Snippet: highlight(t.Title+" — "+t.Content, terms),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// tokenize — Разбиение текста на слова в нижнем регистре
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// countPrefix — Число слов, начинающихся с term
+func countPrefix(words []string, term string) int {
+	n := 0
+	for _, w := range words {
+		if strings.HasPrefix(w, term) {
+			n++
+		}
+	}
+	return n
+}
+
+// highlight — Выделение «ёлочками» слов текста, начинающихся с одного из terms
+func highlight(text string, terms []string) string {
+	var b strings.Builder
+	var word []rune
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		w := string(word)
+		lower := strings.ToLower(w)
+		matched := false
+		for _, term := range terms {
+			if strings.HasPrefix(lower, term) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			b.WriteString("«" + w + "»")
+		} else {
+			b.WriteString(w)
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
@@ -0,0 +1,179 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Sprints — Спринты проекта из контекста в порядке начала
+func (db *DB) Sprints(ctx context.Context) ([]model.Sprint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer db.rlock()()
+	project := storage.Project(ctx)
+	var result []model.Sprint
+	for _, s := range db.sprints {
+		if project == 0 || s.ProjectID == project {
+			result = append(result, s)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Start.Equal(result[j].Start) {
+			return result[i].Start.Before(result[j].Start)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// NewSprint — Создание нового спринта, без проекта - в проекте из контекста
+func (db *DB) NewSprint(ctx context.Context, sprint model.Sprint) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	if err := sprint.Validate(); err != nil {
+		return 0, err
+	}
+	sprint.ProjectID = storage.SprintProject(ctx, sprint)
+	if db.projectIndex(sprint.ProjectID) < 0 {
+		return 0, fmt.Errorf("проект %d: %w", sprint.ProjectID, model.ErrReferenced)
+	}
+	// Даты в postgres хранятся с точностью до секунды
+	sprint.Start = sprint.Start.Truncate(time.Second)
+	sprint.End = sprint.End.Truncate(time.Second)
+	sprint.ID = db.nextSprint
+	db.nextSprint++
+	db.sprints = append(db.sprints, sprint)
+	return sprint.ID, nil
+}
+
+// AddToSprint — Планирование задачи в спринт того же проекта, задача из другого спринта переносится
+func (db *DB) AddToSprint(ctx context.Context, sprintID, taskID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	i := db.activeIndex(taskID)
+	if i < 0 {
+		return fmt.Errorf("задача %d: %w", taskID, model.ErrNotFound)
+	}
+	j := db.sprintIndex(sprintID)
+	switch {
+	case j < 0:
+		return fmt.Errorf("спринт %d: %w", sprintID, model.ErrReferenced)
+	case db.sprints[j].ProjectID != db.tasks[i].ProjectID:
+		return fmt.Errorf("%w: спринт %d из другого проекта, чем задача %d", model.ErrInvalid, sprintID, taskID)
+	case db.tasks[i].SprintID == sprintID:
+		return nil
+	}
+	return db.setSprint(ctx, i, sprintID)
+}
+
+// RemoveFromSprint — Исключение задачи из спринта
+func (db *DB) RemoveFromSprint(ctx context.Context, sprintID, taskID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	i := db.activeIndex(taskID)
+	if i < 0 || db.tasks[i].SprintID != sprintID {
+		return fmt.Errorf("задача %d в спринте %d: %w", taskID, sprintID, model.ErrNotFound)
+	}
+	return db.setSprint(ctx, i, 0)
+}
+
+// CarryOver — Перенос незакрытых задач спринта fromID в спринт toID того же проекта,
+// задачи в корзине остаются в спринте
+func (db *DB) CarryOver(ctx context.Context, fromID, toID int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	from, to := db.sprintIndex(fromID), db.sprintIndex(toID)
+	switch {
+	case from < 0:
+		return 0, fmt.Errorf("спринт %d: %w", fromID, model.ErrNotFound)
+	case to < 0:
+		return 0, fmt.Errorf("спринт %d: %w", toID, model.ErrReferenced)
+	case fromID == toID || db.sprints[from].ProjectID != db.sprints[to].ProjectID:
+		return 0, fmt.Errorf("%w: задачи переносятся в другой спринт того же проекта", model.ErrInvalid)
+	}
+
+	var moved []int
+	var changes []model.Change
+	for i, t := range db.tasks {
+		if t.SprintID == fromID && !t.IsClosed() && !t.IsDeleted() {
+			after := t
+			after.SprintID = toID
+			moved = append(moved, i)
+			changes = append(changes, model.Diff(t, after)...)
+		}
+	}
+	if len(moved) == 0 {
+		return 0, nil
+	}
+	if err := db.record(ctx, changes...); err != nil {
+		return 0, err
+	}
+	for _, i := range moved {
+		db.tasks[i].SprintID = toID
+		db.tasks[i].Version++
+	}
+	return len(moved), nil
+}
+
+// SprintSummary — Итоги спринта по истории задач, которые когда-либо планировались в него
+func (db *DB) SprintSummary(ctx context.Context, sprintID int) (model.SprintSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return model.SprintSummary{}, err
+	}
+	defer db.rlock()()
+	i := db.sprintIndex(sprintID)
+	if i < 0 {
+		return model.SprintSummary{}, fmt.Errorf("спринт %d: %w", sprintID, model.ErrNotFound)
+	}
+	id := strconv.Itoa(sprintID)
+	planned := make(map[int]bool)
+	for _, c := range db.history {
+		if c.Field == model.FieldSprint && (c.Before == id || c.After == id) {
+			planned[c.TaskID] = true
+		}
+	}
+	var changes []model.Change
+	for _, c := range db.history {
+		if planned[c.TaskID] {
+			changes = append(changes, c)
+		}
+	}
+	return model.SummarizeSprint(db.sprints[i], changes, time.Now()), nil
+}
+
+// sprintIndex — Индекс спринта в срезе по id, -1 если спринта нет
+func (db *DB) sprintIndex(id int) int {
+	for i, s := range db.sprints {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// setSprint — Перенос задачи с индексом i в спринт sprintID, 0 - исключение из спринта
+func (db *DB) setSprint(ctx context.Context, i, sprintID int) error {
+	t := db.tasks[i]
+	t.SprintID = sprintID
+	if err := db.record(ctx, model.Diff(db.tasks[i], t)...); err != nil {
+		return err
+	}
+	t.Version++
+	db.tasks[i] = t
+	return nil
+}
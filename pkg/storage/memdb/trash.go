@@ -0,0 +1,96 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// RestoreTask — Восстановление задачи из корзины
+func (db *DB) RestoreTask(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+	i := db.taskIndex(id)
+	if i < 0 || !db.tasks[i].IsDeleted() {
+		return fmt.Errorf("задача %d в корзине: %w", id, model.ErrNotFound)
+	}
+	if err := db.record(ctx, model.Change{TaskID: id, Action: model.ActionRestore}); err != nil {
+		return err
+	}
+	db.tasks[i].Deleted = time.Time{}
+	db.tasks[i].Version++
+	return nil
+}
+
+// PurgeTasks — Окончательное удаление задач проекта из контекста, перемещённых в корзину не позже before
+func (db *DB) PurgeTasks(ctx context.Context, before time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer db.lock()()
+	// Время в postgres хранится с точностью до секунды
+	before = before.Truncate(time.Second)
+	project := storage.Project(ctx)
+	purged := make(map[int]bool)
+	var changes []model.Change
+	for _, t := range db.tasks {
+		if t.IsDeleted() && inProject(t, project) && !t.Deleted.After(before) {
+			purged[t.ID] = true
+			changes = append(changes, model.Change{TaskID: t.ID, Action: model.ActionPurge, Before: t.Title})
+		}
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+	for _, child := range db.tasks {
+		if purged[child.ParentID] && !purged[child.ID] {
+			orphan := child
+			orphan.ParentID = 0
+			changes = append(changes, model.Diff(child, orphan)...)
+		}
+	}
+	if err := db.record(ctx, changes...); err != nil {
+		return 0, err
+	}
+
+	var tasks []model.Task
+	for _, t := range db.tasks {
+		if purged[t.ID] {
+			continue
+		}
+		// Подзадачи становятся задачами верхнего уровня, как ON DELETE SET NULL
+		if purged[t.ParentID] {
+			t.ParentID = 0
+		}
+		tasks = append(tasks, t)
+	}
+	db.tasks = tasks
+	// Зависимости, комментарии и метки удаляются вместе с задачей, как ON DELETE CASCADE
+	var deps []dependency
+	for _, d := range db.dependencies {
+		if !purged[d.taskID] && !purged[d.blockerID] {
+			deps = append(deps, d)
+		}
+	}
+	db.dependencies = deps
+	var comments []model.Comment
+	for _, c := range db.comments {
+		if !purged[c.TaskID] {
+			comments = append(comments, c)
+		}
+	}
+	db.comments = comments
+	var links []taskLabel
+	for _, l := range db.taskLabels {
+		if !purged[l.taskID] {
+			links = append(links, l)
+		}
+	}
+	db.taskLabels = links
+	return len(purged), nil
+}
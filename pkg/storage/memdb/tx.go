@@ -0,0 +1,67 @@
+package memdb
+
+import (
+	"context"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// WithTx — Выполнение fn как единого целого: fn работает с копией БД,
+// которая заменяет собой БД, только если fn не вернула ошибку.
+// На время fn БД заблокирована, поэтому транзакции выполняются по очереди.
+func (db *DB) WithTx(ctx context.Context, fn func(tx storage.Interface) error) error {
+	return db.withTx(ctx, func(tx *DB) error { return fn(tx) })
+}
+
+// withTx — WithTx для методов БД: fn получает *DB с доступом к данным напрямую
+func (db *DB) withTx(ctx context.Context, fn func(tx *DB) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer db.lock()()
+
+	tx := &DB{locked: true, data: db.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	db.data = tx.data
+	return nil
+}
+
+// lock — Захват блокировки на запись, возвращает функцию её снятия.
+// Внутри транзакции блокировка уже захвачена WithTx.
+func (db *DB) lock() func() {
+	if db.locked {
+		return func() {}
+	}
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+// rlock — Захват блокировки на чтение, возвращает функцию её снятия.
+func (db *DB) rlock() func() {
+	if db.locked {
+		return func() {}
+	}
+	db.mu.RLock()
+	return db.mu.RUnlock
+}
+
+// clone — Копия содержимого БД, не разделяющая срезы с оригиналом
+func (d *data) clone() data {
+	c := *d
+	c.tasks = append([]model.Task(nil), d.tasks...)
+	c.taskLabels = append([]taskLabel(nil), d.taskLabels...)
+	c.dependencies = append([]dependency(nil), d.dependencies...)
+	c.labels = append([]model.Label(nil), d.labels...)
+	c.users = append([]model.User(nil), d.users...)
+	c.statuses = append([]model.Status(nil), d.statuses...)
+	c.transitions = append([]model.Transition(nil), d.transitions...)
+	c.comments = append([]model.Comment(nil), d.comments...)
+	c.history = append([]model.Change(nil), d.history...)
+	c.projects = append([]model.Project(nil), d.projects...)
+	c.members = append([]projectMember(nil), d.members...)
+	c.sprints = append([]model.Sprint(nil), d.sprints...)
+	return c
+}
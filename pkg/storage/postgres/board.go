@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/rank"
+	"task-meneger/pkg/storage"
+)
+
+// Board возвращает канбан-доску проекта из контекста: колонки по статусам
+// в порядке процесса, задачи в колонках - в порядке рангов.
+// Задачи в корзине на доску не попадают.
+func (s *Storage) Board(ctx context.Context) ([]model.Column, error) {
+	statuses, err := s.Statuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE t.deleted = 0 AND ($1 = 0 OR t.project_id = $1)
+		ORDER BY t.rank, t.id;
+	`, storage.Project(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении задач доски: %w", dbError(err))
+	}
+
+	columns := make([]model.Column, len(statuses))
+	index := make(map[int]int, len(statuses))
+	for i, st := range statuses {
+		columns[i].Status = st
+		index[st.ID] = i
+	}
+	for _, t := range tasks {
+		i := index[t.StatusID]
+		columns[i].Tasks = append(columns[i].Tasks, t)
+	}
+	return columns, nil
+}
+
+// MoveTask перемещает задачу в колонку статуса statusID сразу после задачи afterID,
+// при afterID = 0 - в начало колонки. При statusID = 0 задача переставляется
+// внутри своей колонки. Смена статуса проверяется по разрешённым переходам
+// и записывается в историю, как в UpdateTask.
+// Если задачи afterID нет в колонке или она из другого проекта, возвращается model.ErrInvalid.
+func (s *Storage) MoveTask(ctx context.Context, taskID, statusID, afterID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		before, err := tx.lockTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if statusID == 0 {
+			statusID = before.StatusID
+		}
+		if statusID != before.StatusID {
+			if err := tx.checkTransition(ctx, taskID, before.StatusID, statusID); err != nil {
+				return err
+			}
+		}
+
+		// Соседи задачи на новом месте: prev - задача afterID, next - следующая за ней
+		var prev string
+		if afterID != 0 {
+			var afterStatus, afterProject int
+			err := tx.db.QueryRow(ctx, `
+				SELECT status_id, project_id, rank FROM tasks WHERE id = $1 AND deleted = 0;
+			`, afterID).Scan(&afterStatus, &afterProject, &prev)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("задача %d: %w", afterID, model.ErrNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("ошибка при получении задачи %d: %w", afterID, dbError(err))
+			}
+			if afterID == taskID || afterStatus != statusID {
+				return fmt.Errorf("%w: задача %d не стоит в колонке статуса %d", model.ErrInvalid, afterID, statusID)
+			}
+			if afterProject != before.ProjectID {
+				return fmt.Errorf("%w: задача %d из другого проекта, чем задача %d", model.ErrInvalid, afterID, taskID)
+			}
+		}
+		var next string
+		err = tx.db.QueryRow(ctx, `
+			SELECT COALESCE(min(rank), '') FROM tasks
+			WHERE status_id = $1 AND deleted = 0 AND id <> $2 AND rank > $3 AND project_id = $4;
+		`, statusID, taskID, prev, before.ProjectID).Scan(&next)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении соседней задачи: %w", dbError(err))
+		}
+		r, err := rank.Between(prev, next)
+		if err != nil {
+			return fmt.Errorf("ошибка при вычислении ранга задачи %d: %w", taskID, err)
+		}
+
+		_, err = tx.db.Exec(ctx, `
+			UPDATE tasks SET status_id = $1, rank = $2, version = version + 1
+			WHERE id = $3;
+		`, statusID, r, taskID)
+		if err != nil {
+			return fmt.Errorf("ошибка при перемещении задачи: %w", dbError(err))
+		}
+
+		after, err := tx.lockTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.Diff(before, after)...)
+	})
+}
+
+// lastRank возвращает ранг после рангов всех задач проекта projectID:
+// задача с ним встаёт в конец любой колонки доски проекта.
+func (s *Storage) lastRank(ctx context.Context, projectID int) (string, error) {
+	var last string
+	err := s.db.QueryRow(ctx, `
+		SELECT COALESCE(max(rank), '') FROM tasks WHERE project_id = $1;
+	`, projectID).Scan(&last)
+	if err != nil {
+		return "", fmt.Errorf("ошибка при получении ранга задач: %w", dbError(err))
+	}
+	r, err := rank.Between(last, "")
+	if err != nil {
+		return "", fmt.Errorf("ошибка при вычислении ранга задачи: %w", err)
+	}
+	return r, nil
+}
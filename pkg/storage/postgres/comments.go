@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+)
+
+// Comments возвращает комментарии к задаче в хронологическом порядке.
+func (s *Storage) Comments(ctx context.Context, taskID int) ([]model.Comment, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT c.id, c.task_id, u.id, u.name, c.created, c.edited, c.content
+		FROM comments c
+		JOIN users u ON u.id = c.author_id
+		WHERE c.task_id = $1
+		ORDER BY c.created, c.id;
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении комментариев: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+
+	for rows.Next() {
+		var c model.Comment
+		err := rows.Scan(
+			&c.ID,
+			&c.TaskID,
+			&c.Author.ID,
+			&c.Author.Name,
+			epoch{&c.Created},
+			epoch{&c.Edited},
+			&c.Content,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании комментария: %w", dbError(err))
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return comments, nil
+}
+
+// NewComment добавляет комментарий к задаче и возвращает его id.
+func (s *Storage) NewComment(ctx context.Context, c model.Comment) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO comments (task_id, author_id, content)
+		VALUES ($1, $2, $3)
+		RETURNING id;
+	`, c.TaskID, c.Author.ID, c.Content).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при создании комментария: %w", dbError(err))
+	}
+	return id, nil
+}
+
+// UpdateComment изменяет текст комментария и отмечает время редактирования.
+func (s *Storage) UpdateComment(ctx context.Context, c model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, `
+		UPDATE comments
+		SET content = $1, edited = extract(epoch from now())
+		WHERE id = $2;
+	`, c.Content, c.ID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении комментария: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("комментарий %d: %w", c.ID, model.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteComment удаляет комментарий по id.
+func (s *Storage) DeleteComment(ctx context.Context, id int) error {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM comments WHERE id = $1;
+	`, id)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении комментария: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("комментарий %d: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv сбрасывает переменные окружения настроек БД на время теста.
+func clearEnv(t *testing.T) {
+	for _, k := range configKeys {
+		t.Setenv(k.env, "")
+	}
+}
+
+// writeConfig записывает файл настроек во временный каталог.
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `{
+		"host": "db.local",
+		"port": 6432,
+		"user": "app",
+		"password": "p@ss/word",
+		"name": "tasks",
+		"sslmode": "require",
+		"max_conns": 8,
+		"statement_timeout": "30s",
+		"auto_migrate": true
+	}`)
+	t.Setenv("DB_HOST", "override.local")
+	t.Setenv("DB_MAX_CONNS", "16")
+
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	want := Config{
+		Host:             "override.local",
+		Port:             6432,
+		User:             "app",
+		Password:         "p@ss/word",
+		Name:             "tasks",
+		SSLMode:          "require",
+		MaxConns:         16,
+		StatementTimeout: 30 * time.Second,
+		AutoMigrate:      true,
+	}
+	if c != want {
+		t.Fatalf("LoadConfig() = %+v, want %+v", c, want)
+	}
+
+	pc, err := c.poolConfig()
+	if err != nil {
+		t.Fatalf("poolConfig() error = %v", err)
+	}
+	if pc.ConnConfig.Host != "override.local" || pc.ConnConfig.Password != "p@ss/word" || pc.MaxConns != 16 {
+		t.Errorf("poolConfig() host = %q, password = %q, max_conns = %d", pc.ConnConfig.Host, pc.ConnConfig.Password, pc.MaxConns)
+	}
+	if got := pc.ConnConfig.RuntimeParams["statement_timeout"]; got != "30000" {
+		t.Errorf("poolConfig() statement_timeout = %q, want 30000", got)
+	}
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_HOST", "db.local")
+	t.Setenv("DB_USER", "app")
+	t.Setenv("DB_NAME", "tasks")
+
+	c, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	want := Config{Host: "db.local", Port: 5432, User: "app", Name: "tasks", SSLMode: "disable"}
+	if c != want {
+		t.Fatalf("LoadConfig() = %+v, want %+v", c, want)
+	}
+	if got := c.DSN(); got != "postgres://app:@db.local:5432/tasks?sslmode=disable" {
+		t.Errorf("DSN() = %q", got)
+	}
+}
+
+func TestLoadConfig_URL(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DATABASE_URL", "postgres://app@db.local/tasks?sslmode=verify-full")
+
+	c, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if c.DSN() != "postgres://app@db.local/tasks?sslmode=verify-full" {
+		t.Errorf("DSN() = %q, want DATABASE_URL как есть", c.DSN())
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want string
+	}{
+		{"без адреса", "", nil, "не задан адрес БД"},
+		{"порт", "", map[string]string{"DB_HOST": "h", "DB_USER": "u", "DB_NAME": "n", "DB_PORT": "abc"}, "DB_PORT"},
+		{"sslmode", `{"host": "h", "user": "u", "name": "n", "sslmode": "on"}`, nil, "неизвестный sslmode"},
+		{"пул", `{"host": "h", "user": "u", "name": "n", "max_conns": 2, "min_conns": 4}`, nil, "min_conns"},
+		{"длительность", `{"url": "postgres://h/n", "max_conn_lifetime": "час"}`, nil, "max_conn_lifetime"},
+		{"неизвестная настройка", `{"url": "postgres://h/n", "hots": "h"}`, nil, "hots"},
+		{"не JSON", `host = "h"`, nil, "ошибка в файле настроек"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeConfig(t, tt.file)
+			}
+			_, err := LoadConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfig() error = %v, want ошибку с %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// unblocked — условие WHERE: у задачи t нет открытых блокирующих задач.
+// Задачи в корзине не блокируют.
+const unblocked = `NOT EXISTS (
+	SELECT 1
+	FROM task_dependencies d
+	JOIN tasks b ON b.id = d.blocker_id
+	WHERE d.task_id = t.id AND b.closed = 0 AND b.deleted = 0
+)`
+
+// AddDependency помечает задачу taskID заблокированной задачей blockerID.
+// Если зависимость замыкает цикл, возвращается model.ErrDependencyCycle.
+func (s *Storage) AddDependency(ctx context.Context, taskID, blockerID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		return tx.addDependency(ctx, taskID, blockerID)
+	})
+}
+
+// addDependency проверяет цикл и добавляет зависимость, вызывается внутри транзакции.
+func (s *Storage) addDependency(ctx context.Context, taskID, blockerID int) error {
+	// Цикл возникает, если taskID уже среди блокирующих задач blockerID
+	// (непосредственно или через цепочку зависимостей)
+	var cycle bool
+	err := s.db.QueryRow(ctx, `
+		WITH RECURSIVE chain (id) AS (
+			SELECT $1::int
+			UNION
+			SELECT d.blocker_id
+			FROM task_dependencies d
+			JOIN chain c ON d.task_id = c.id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2);
+	`, blockerID, taskID).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("ошибка при проверке зависимостей: %w", dbError(err))
+	}
+	if cycle {
+		return model.ErrDependencyCycle
+	}
+
+	_, err = s.db.Exec(ctx, `
+		INSERT INTO task_dependencies (task_id, blocker_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`, taskID, blockerID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при добавлении зависимости: %w", dbError(err))
+	}
+	return nil
+}
+
+// RemoveDependency снимает блокировку задачи taskID задачей blockerID.
+func (s *Storage) RemoveDependency(ctx context.Context, taskID, blockerID int) error {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM task_dependencies WHERE task_id = $1 AND blocker_id = $2;
+	`, taskID, blockerID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении зависимости: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("зависимость задачи %d от задачи %d: %w", taskID, blockerID, model.ErrNotFound)
+	}
+	return nil
+}
+
+// Blockers возвращает задачи, которыми заблокирована задача.
+func (s *Storage) Blockers(ctx context.Context, taskID int) ([]model.Task, error) {
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE
+			t.id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = $1) AND
+			t.deleted = 0
+		ORDER BY t.id;
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении блокирующих задач: %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// UnblockedTasks возвращает открытые задачи, все блокирующие задачи
+// которых закрыты (или которые ничем не заблокированы).
+func (s *Storage) UnblockedTasks(ctx context.Context) ([]model.Task, error) {
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE t.closed = 0 AND t.deleted = 0 AND ($1 = 0 OR t.project_id = $1) AND `+unblocked+`
+		ORDER BY t.priority DESC, t.id;
+	`, storage.Project(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении незаблокированных задач: %w", dbError(err))
+	}
+	return tasks, nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+)
+
+// epoch — время, которое хранится в БД числом секунд Unix (BIGINT).
+// Значения 0 и NULL соответствуют нулевому time.Time.
+// Используется как приёмник при сканировании: epoch{&t.Opened}.
+type epoch struct {
+	t *time.Time
+}
+
+// Scan реализует sql.Scanner.
+func (e epoch) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*e.t = time.Time{}
+	case int64:
+		*e.t = fromUnix(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип времени %T", src)
+	}
+	return nil
+}
+
+// fromUnix переводит секунды Unix во время, 0 - нулевое время.
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// unix переводит время в секунды Unix для записи в БД, нулевое время - 0.
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
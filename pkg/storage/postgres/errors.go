@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"task-meneger/pkg/model"
+)
+
+// Коды ошибок PostgreSQL, которые приводятся к ошибкам хранилища.
+const (
+	codeNotNullViolation     = "23502"
+	codeForeignKeyViolation  = "23503"
+	codeUniqueViolation      = "23505"
+	codeCheckViolation       = "23514"
+	codeInvalidText          = "22P02"
+	codeSerializationFailure = "40001"
+)
+
+// dbError приводит ошибку pgx к одной из ошибок хранилища,
+// сохраняя исходную ошибку в цепочке. Остальные ошибки возвращаются как есть.
+func dbError(err error) error {
+	for _, target := range []error{model.ErrNotFound, model.ErrConflict, model.ErrInvalid, model.ErrReferenced} {
+		if errors.Is(err, target) {
+			return err
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", model.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case codeForeignKeyViolation:
+		return fmt.Errorf("%w: %w", model.ErrReferenced, err)
+	case codeUniqueViolation, codeSerializationFailure:
+		return fmt.Errorf("%w: %w", model.ErrConflict, err)
+	case codeNotNullViolation, codeCheckViolation, codeInvalidText:
+		return fmt.Errorf("%w: %w", model.ErrInvalid, err)
+	}
+	return err
+}
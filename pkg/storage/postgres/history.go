@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// History возвращает историю изменений задачи в хронологическом порядке.
+// История удалённой задачи сохраняется.
+func (s *Storage) History(ctx context.Context, taskID int) ([]model.Change, error) {
+	changes, err := s.queryChanges(ctx, `
+		SELECT `+changeColumns+`
+		FROM task_history h
+		JOIN users u ON u.id = h.actor_id
+		WHERE h.task_id = $1
+		ORDER BY h.at, h.id;
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории задачи: %w", dbError(err))
+	}
+	return changes, nil
+}
+
+// changeColumns — столбцы записи истории для SELECT-запросов,
+// h - псевдоним task_history, u - users.
+const changeColumns = `h.id, h.task_id, u.id, u.name, h.at, h.action, h.field, h.before, h.after`
+
+// queryChanges выполняет запрос, выбирающий столбцы changeColumns,
+// и возвращает найденные записи истории.
+func (s *Storage) queryChanges(ctx context.Context, query string, args ...interface{}) ([]model.Change, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []model.Change
+
+	for rows.Next() {
+		var c model.Change
+		err := rows.Scan(
+			&c.ID,
+			&c.TaskID,
+			&c.Actor.ID,
+			&c.Actor.Name,
+			epoch{&c.Time},
+			&c.Action,
+			&c.Field,
+			&c.Before,
+			&c.After,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании истории задачи: %w", dbError(err))
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return changes, nil
+}
+
+// record записывает изменения задач в историю от имени пользователя из ctx
+// (см. storage.WithActor). Вызывается в той же транзакции, что и изменение.
+func (s *Storage) record(ctx context.Context, changes ...model.Change) error {
+	actor := storage.Actor(ctx)
+	for _, c := range changes {
+		_, err := s.db.Exec(ctx, `
+			INSERT INTO task_history (task_id, actor_id, action, field, before, after)
+			VALUES ($1, $2, $3, $4, $5, $6);
+		`, c.TaskID, actor, c.Action, c.Field, c.Before, c.After)
+		if err != nil {
+			return fmt.Errorf("ошибка при записи истории задачи %d: %w", c.TaskID, dbError(err))
+		}
+	}
+	return nil
+}
+
+// lockTask возвращает задачу и блокирует её строку до конца транзакции.
+// Если задачи нет или она в корзине, возвращается model.ErrNotFound.
+func (s *Storage) lockTask(ctx context.Context, taskID int) (model.Task, error) {
+	var t model.Task
+	err := s.db.QueryRow(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE t.id = $1 AND t.deleted = 0
+		FOR UPDATE OF t;
+	`, taskID).Scan(taskFields(&t)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return t, fmt.Errorf("задача %d: %w", taskID, model.ErrNotFound)
+	}
+	if err != nil {
+		return t, fmt.Errorf("ошибка при получении задачи %d: %w", taskID, dbError(err))
+	}
+	return t, nil
+}
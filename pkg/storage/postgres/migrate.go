@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Файлы миграций вида 0001_init.up.sql и 0001_init.down.sql.
+// Номер версии задаёт порядок применения, up применяет миграцию, down откатывает.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Ключ advisory-блокировки, чтобы миграции не запускались одновременно
+// из нескольких процессов.
+const migrationLock = 20260101
+
+// Миграция схемы БД.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// Состояние миграции в БД.
+type MigrationStatus struct {
+	Migration
+	Applied time.Time // время применения, нулевое - миграция не применена
+}
+
+// Migrations возвращает все миграции по возрастанию версии.
+func Migrations() ([]Migration, error) {
+	files, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при чтении миграций: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, file := range files {
+		base := strings.TrimPrefix(file, "migrations/")
+		name, direction, ok := strings.Cut(strings.TrimSuffix(base, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("некорректное имя файла миграции %s", base)
+		}
+		number, name, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная версия миграции %s: %w", base, err)
+		}
+		body, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при чтении миграции %s: %w", base, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("у миграции %d нет файла up или down", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MigrateUp применяет все ещё не применённые миграции и возвращает их.
+func (s *Storage) MigrateUp(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := s.withMigrationLock(ctx, func(conn *pgxpool.Conn, applied map[int]time.Time) error {
+		migrations, err := Migrations()
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			err := runMigration(ctx, conn, m.up, `
+				INSERT INTO schema_migrations (version, name) VALUES ($1, $2);
+			`, m.Version, m.Name)
+			if err != nil {
+				return fmt.Errorf("ошибка при применении миграции %d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrateDown откатывает steps последних применённых миграций
+// (все, если steps <= 0) и возвращает их.
+func (s *Storage) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := s.withMigrationLock(ctx, func(conn *pgxpool.Conn, applied map[int]time.Time) error {
+		migrations, err := Migrations()
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0; i-- {
+			if steps > 0 && len(done) == steps {
+				break
+			}
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			err := runMigration(ctx, conn, m.down, `
+				DELETE FROM schema_migrations WHERE version = $1;
+			`, m.Version)
+			if err != nil {
+				return fmt.Errorf("ошибка при откате миграции %d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrationStatus возвращает все миграции с отметкой о применении.
+func (s *Storage) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	var status []MigrationStatus
+	err := s.withMigrationLock(ctx, func(conn *pgxpool.Conn, applied map[int]time.Time) error {
+		migrations, err := Migrations()
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			status = append(status, MigrationStatus{Migration: m, Applied: applied[m.Version]})
+		}
+		return nil
+	})
+	return status, err
+}
+
+// withMigrationLock берёт advisory-блокировку на отдельном соединении,
+// создаёт таблицу schema_migrations и вызывает fn с применёнными версиями.
+func (s *Storage) withMigrationLock(ctx context.Context, fn func(*pgxpool.Conn, map[int]time.Time) error) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения к БД: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1);`, migrationLock); err != nil {
+		return fmt.Errorf("ошибка при блокировке миграций: %w", dbError(err))
+	}
+	// контекст может быть уже отменён, а блокировку нужно снять в любом случае
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1);`, migrationLock)
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied BIGINT NOT NULL DEFAULT extract(epoch from now())
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка при создании таблицы миграций: %w", dbError(err))
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version, applied FROM schema_migrations;`)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении применённых миграций: %w", dbError(err))
+	}
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, epoch{&at}); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка при получении применённых миграций: %w", dbError(err))
+		}
+		applied[version] = at
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при получении применённых миграций: %w", dbError(err))
+	}
+
+	return fn(conn, applied)
+}
+
+// runMigration выполняет SQL миграции и запись в schema_migrations
+// в одной транзакции.
+func runMigration(ctx context.Context, conn *pgxpool.Conn, sql, record string, args ...interface{}) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка при начале транзакции: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return dbError(err)
+	}
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return dbError(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка при фиксации транзакции: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS comments, task_dependencies, tasks_labels, tasks, labels, users, status_transitions, statuses;
@@ -0,0 +1,139 @@
+/*
+    Начальная схема БД для информационной системы
+    отслеживания выполнения задач.
+
+    Таблицы создаются только если их ещё нет, чтобы миграция
+    применялась и к БД, созданным раньше из schema.sql любой версии:
+    недостающие в таких БД столбцы, индексы и ограничения добавляются
+    в конце миграции, после начальных данных.
+*/
+
+-- пользователи системы
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+-- метки задач
+CREATE TABLE IF NOT EXISTS labels (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+-- статусы задач (backlog, in progress, review, done ...)
+CREATE TABLE IF NOT EXISTS statuses (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0 -- порядок статуса в процессе
+);
+
+-- разрешённые переходы между статусами
+CREATE TABLE IF NOT EXISTS status_transitions (
+    from_id INTEGER REFERENCES statuses(id),
+    to_id INTEGER REFERENCES statuses(id),
+    PRIMARY KEY (from_id, to_id)
+);
+
+-- задачи
+CREATE TABLE IF NOT EXISTS tasks (
+    id SERIAL PRIMARY KEY,
+    opened BIGINT NOT NULL DEFAULT extract(epoch from now()), -- время создания задачи
+    closed BIGINT DEFAULT 0, -- время выполнения задачи
+    author_id INTEGER REFERENCES users(id) DEFAULT 0, -- автор задачи
+    assigned_id INTEGER REFERENCES users(id) DEFAULT 0, -- ответственный
+    status_id INTEGER REFERENCES statuses(id) DEFAULT 1, -- статус задачи
+    priority INTEGER NOT NULL DEFAULT 0, -- приоритет: 0 - низкий ... 3 - критический
+    due BIGINT DEFAULT 0, -- срок выполнения задачи, 0 - без срока
+    parent_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL, -- родительская задача
+    title TEXT, -- название задачи
+    content TEXT, -- задачи
+    -- полнотекстовый индекс по названию и тексту задачи,
+    -- текст смешанный, поэтому используются русская и английская конфигурации
+    search tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(content, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'B')
+    ) STORED
+);
+
+-- связь многие - ко- многим между задачами и метками
+CREATE TABLE IF NOT EXISTS tasks_labels (
+    task_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE,
+    label_id INTEGER REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+-- зависимости между задачами: задача task_id заблокирована задачей blocker_id
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE,
+    blocker_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, blocker_id)
+);
+
+-- комментарии к задачам
+CREATE TABLE IF NOT EXISTS comments (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES users(id) DEFAULT 0, -- автор комментария
+    created BIGINT NOT NULL DEFAULT extract(epoch from now()), -- время создания
+    edited BIGINT DEFAULT 0, -- время последнего редактирования, 0 - не редактировался
+    content TEXT NOT NULL
+);
+-- наполнение БД начальными данными
+INSERT INTO users (id, name) VALUES (0, 'default') ON CONFLICT DO NOTHING;
+-- процесс работы по умолчанию: backlog → in progress → review → done
+INSERT INTO statuses (id, name, position) VALUES
+    (1, 'backlog', 1),
+    (2, 'in progress', 2),
+    (3, 'review', 3),
+    (4, 'done', 4)
+ON CONFLICT DO NOTHING;
+SELECT setval('statuses_id_seq', GREATEST(4, (SELECT max(id) FROM statuses)));
+INSERT INTO status_transitions (from_id, to_id) VALUES
+    (1, 2), (2, 1), (2, 3), (3, 2), (3, 4), (4, 2)
+ON CONFLICT DO NOTHING;
+
+/*
+    Обновление БД, созданных из schema.sql до появления статусов,
+    приоритетов, подзадач, поиска и ограничений на метки задач.
+    Для новых БД эти шаги ничего не меняют.
+*/
+
+-- столбцы задач, добавленные после первой версии схемы;
+-- статус 1 уже создан выше, поэтому старые задачи попадают в backlog
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS status_id INTEGER REFERENCES statuses(id) DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS due BIGINT DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(content, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS tasks_search_idx ON tasks USING GIN (search);
+
+-- в первой версии схемы у меток задач не было первичного ключа:
+-- повторы и неполные строки удаляются перед его добавлением
+DELETE FROM tasks_labels WHERE task_id IS NULL OR label_id IS NULL;
+DELETE FROM tasks_labels a
+USING tasks_labels b
+WHERE a.ctid > b.ctid AND a.task_id = b.task_id AND a.label_id = b.label_id;
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'tasks_labels'::regclass AND contype = 'p'
+    ) THEN
+        ALTER TABLE tasks_labels ADD PRIMARY KEY (task_id, label_id);
+    END IF;
+END $$;
+
+-- метки задачи удаляются вместе с задачей и с меткой
+ALTER TABLE tasks_labels
+    DROP CONSTRAINT IF EXISTS tasks_labels_task_id_fkey,
+    DROP CONSTRAINT IF EXISTS tasks_labels_label_id_fkey,
+    ADD CONSTRAINT tasks_labels_task_id_fkey FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    ADD CONSTRAINT tasks_labels_label_id_fkey FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+-- версия задачи для оптимистичной блокировки: UpdateTask проверяет,
+-- что задачу не изменили с момента чтения, и увеличивает версию
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+-- история изменений задач: кто, когда и что изменил.
+-- Изменение полей задачи записывается отдельной строкой на каждое поле.
+-- Ссылки на tasks нет, чтобы история удалённой задачи сохранялась.
+CREATE TABLE task_history (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL,
+    actor_id INTEGER NOT NULL REFERENCES users(id), -- кто изменил задачу
+    at BIGINT NOT NULL DEFAULT extract(epoch from now()), -- время изменения
+    action TEXT NOT NULL, -- create, update, delete, close, reopen, label, unlabel
+    field TEXT NOT NULL DEFAULT '', -- изменённое поле для update
+    before TEXT NOT NULL DEFAULT '', -- значение до изменения
+    after TEXT NOT NULL DEFAULT '' -- значение после изменения
+);
+CREATE INDEX task_history_task_idx ON task_history (task_id, at);
@@ -0,0 +1,3 @@
+-- корзина: время перемещения задачи в корзину, 0 - задача не удалена
+ALTER TABLE tasks ADD COLUMN deleted BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX tasks_deleted_idx ON tasks (deleted) WHERE deleted <> 0;
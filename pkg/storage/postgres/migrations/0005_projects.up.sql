@@ -0,0 +1,20 @@
+-- проекты, в которых ведутся задачи
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+-- проект по умолчанию, в него попадают уже созданные задачи
+INSERT INTO projects (id, name) VALUES (1, 'Общий');
+SELECT setval('projects_id_seq', 1);
+
+-- участники проектов
+CREATE TABLE project_members (
+    project_id INTEGER REFERENCES projects(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (project_id, user_id)
+);
+
+-- проект задачи, проект с задачами удалить нельзя
+ALTER TABLE tasks ADD COLUMN project_id INTEGER NOT NULL DEFAULT 1 REFERENCES projects(id);
+CREATE INDEX tasks_project_idx ON tasks (project_id);
@@ -0,0 +1,8 @@
+-- ранг задачи в колонке доски, сравнивается побайтно (см. пакет rank)
+ALTER TABLE tasks ADD COLUMN rank TEXT COLLATE "C" NOT NULL DEFAULT '';
+-- уже созданные задачи стоят в колонках в порядке id,
+-- ранги одной длины и не оканчиваются на "0", как требует пакет rank
+UPDATE tasks t SET rank = lpad(r.n::text, 5, '0') || 'i'
+FROM (SELECT id, row_number() OVER (PARTITION BY status_id ORDER BY id) AS n FROM tasks) r
+WHERE r.id = t.id;
+CREATE INDEX tasks_rank_idx ON tasks (status_id, rank);
@@ -0,0 +1,13 @@
+-- спринты проектов, даты начала и окончания - секунды Unix, как у задач
+CREATE TABLE sprints (
+    id SERIAL PRIMARY KEY,
+    project_id INTEGER NOT NULL REFERENCES projects(id),
+    name TEXT NOT NULL,
+    starts BIGINT NOT NULL,
+    ends BIGINT NOT NULL
+);
+CREATE INDEX sprints_project_idx ON sprints (project_id, starts);
+
+-- спринт, в который запланирована задача
+ALTER TABLE tasks ADD COLUMN sprint_id INTEGER REFERENCES sprints(id);
+CREATE INDEX tasks_sprint_idx ON tasks (sprint_id);
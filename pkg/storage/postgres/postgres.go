@@ -2,121 +2,297 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
 )
 
 // Хранилище данных.
 type Storage struct {
-	db *pgxpool.Pool //ПУЛ СОЕДИНЕНИЙ
+	pool *pgxpool.Pool //ПУЛ СОЕДИНЕНИЙ
+	db   querier       // пул или текущая транзакция, см. WithTx
 }
 
-// Функция New - подключение к БД
-func New() (*Storage, error) {
-
-	// err := godotenv.Load("../../../.env")
-	// if err != nil {
-	// 	log.Println("Не удалось загрузить .env файл, используем переменные окружения")
-	// }
-
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-
-	// log.Println("DB_HOST:", os.Getenv("DB_HOST"))
-	// log.Println("DB_PORT:", os.Getenv("DB_PORT"))
-	// log.Println("DB_USER:", os.Getenv("DB_USER"))
-	// log.Println("DB_NAME:", os.Getenv("DB_NAME"))
-
-	// host := "localhost"
-	// port := "5433"
-	// user := "postgres"
-	// password := "2606"
-	// dbname := "db_task_manager"
+var _ storage.Interface = (*Storage)(nil)
 
-	// для теста енв файла, если проблемы
-	// log.Printf("DB_HOST=%s DB_PORT=%s DB_USER=%s DB_NAME=%s", host, port, user, dbname)
+// Функция New - подключение к БД
+// Настройки читаются из файла DB_CONFIG (если задан) и переменных окружения, см. LoadConfig
+func New(ctx context.Context) (*Storage, error) {
+	cfg, err := LoadConfig(os.Getenv("DB_CONFIG"))
+	if err != nil {
+		return nil, err
+	}
+	return Connect(ctx, cfg)
+}
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		user, password, host, port, dbname)
+// Функция Connect - подключение к БД с заданными настройками
+func Connect(ctx context.Context, cfg Config) (*Storage, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	pc, err := cfg.poolConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	dbpool, err := pgxpool.Connect(context.Background(), dsn)
+	dbpool, err := pgxpool.ConnectConfig(ctx, pc)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к БД: %w", err)
 	}
 
-	return &Storage{db: dbpool}, nil
+	s := &Storage{pool: dbpool, db: dbpool}
+
+	if cfg.AutoMigrate {
+		if _, err := s.MigrateUp(ctx); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
 }
 
 // Закрытие соединения с БД
 func (s *Storage) Close() {
-	s.db.Close()
+	// Хранилище транзакции из WithTx разделяет пул с основным,
+	// пул закрывает только основное хранилище
+	if s.db != querier(s.pool) {
+		return
+	}
+	s.pool.Close()
 }
 
-// Задача.
-type Task struct {
-	ID         int
-	Opened     int64
-	Closed     int64
-	AuthorID   int
-	AssignedID int
-	Title      string
-	Content    string
+// taskColumns — столбцы задачи для SELECT-запросов.
+// Порядок столбцов совпадает с порядком сканирования в scanTasks.
+const taskColumns = `
+	t.id,
+	t.opened,
+	t.closed,
+	t.author_id,
+	t.assigned_id,
+	t.status_id,
+	s.name,
+	t.priority,
+	t.due,
+	COALESCE(t.parent_id, 0),
+	t.title,
+	t.content,
+	t.version,
+	t.deleted,
+	t.project_id,
+	t.rank,
+	COALESCE(t.sprint_id, 0)
+`
+
+// sortColumns — выражения для сортировки задач, %[1]s - псевдоним таблицы tasks.
+var sortColumns = map[string]string{
+	model.SortID:     "%[1]s.id",
+	model.SortOpened: "%[1]s.opened",
+	model.SortClosed: "%[1]s.closed",
+	model.SortTitle:  "COALESCE(%[1]s.title, '')",
 }
 
-// Метка.
-type Label struct {
-	ID   int
-	Name string
-}
+// Tasks возвращает список задач из БД, отобранных по фильтру.
+// Поддерживается сортировка, постраничный вывод через Limit/Offset
+// и keyset-пагинация через After/Before.
+func (s *Storage) Tasks(ctx context.Context, f model.TaskFilter) ([]model.Task, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if f.Sort == "" {
+		f.Sort = model.SortID
+	}
+	column := sortColumns[f.Sort]
+
+	// Для Before выбираем задачи в обратном порядке,
+	// а затем разворачиваем результат
+	asc, cursor := !f.Desc, f.After
+	if f.Before != 0 {
+		asc, cursor = f.Desc, f.Before
+	}
+	dir, cmp := "ASC", ">"
+	if !asc {
+		dir, cmp = "DESC", "<"
+	}
+	key := fmt.Sprintf(column, "t")
+
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE
+			($1 = 0 OR t.id = $1) AND
+			($2 = 0 OR t.author_id = $2) AND
+			($3 = 0 OR t.assigned_id = $3) AND
+			(COALESCE(cardinality($4::int[]), 0) = 0 OR EXISTS (
+				SELECT 1 FROM tasks_labels tl
+				WHERE tl.task_id = t.id AND tl.label_id = ANY($4)
+			)) AND
+			NOT EXISTS (
+				SELECT 1 FROM unnest($5::int[]) AS l (id)
+				WHERE NOT EXISTS (
+					SELECT 1 FROM tasks_labels tl
+					WHERE tl.task_id = t.id AND tl.label_id = l.id
+				)
+			) AND
+			($6::bigint = 0 OR t.opened >= $6) AND
+			($7::bigint = 0 OR t.opened <= $7) AND
+			($8::bigint = 0 OR t.closed >= $8) AND
+			($9::bigint = 0 OR (t.closed <> 0 AND t.closed <= $9)) AND
+			($10 = 0 OR ($10 = 1 AND t.closed = 0) OR ($10 = 2 AND t.closed <> 0)) AND
+			(t.deleted <> 0) = $14 AND
+			($15 = 0 OR t.project_id = $15) AND
+			($16 = 0 OR t.sprint_id = $16) AND
+			($11 = 0 OR (`+key+`, t.id) `+cmp+` (
+				SELECT `+fmt.Sprintf(column, "c")+`, c.id FROM tasks c WHERE c.id = $11
+			))
+		ORDER BY `+key+` `+dir+`, t.id `+dir+`
+		LIMIT NULLIF($12, 0) OFFSET $13;
+	`,
+		f.TaskID,
+		f.AuthorID,
+		f.AssignedID,
+		f.LabelsAny,
+		f.LabelsAll,
+		unix(f.OpenedFrom),
+		unix(f.OpenedTo),
+		unix(f.ClosedFrom),
+		unix(f.ClosedTo),
+		f.State,
+		cursor,
+		f.Limit,
+		f.Offset,
+		f.Trash,
+		storage.Project(ctx),
+		f.SprintID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка задач: %w", dbError(err))
+	}
 
-// Пользователь.
-type User struct {
-	ID   int
-	Name string
+	if f.Before != 0 {
+		for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+			tasks[i], tasks[j] = tasks[j], tasks[i]
+		}
+	}
+	return tasks, nil
 }
 
-// Tasks возвращает список задач из БД.
-func (s *Storage) Tasks(taskID, authorID int) ([]Task, error) {
-	rows, err := s.db.Query(context.Background(), `
-		SELECT 
-			id,
-			opened,
-			closed,
-			author_id,
-			assigned_id,
-			title,
-			content
-		FROM tasks
+// OverdueTasks возвращает открытые задачи, срок которых истёк
+// или истекает в ближайшие soon, от самых приоритетных к менее важным.
+func (s *Storage) OverdueTasks(ctx context.Context, soon time.Duration) ([]model.Task, error) {
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
 		WHERE
-			($1 = 0 OR id = $1) AND
-			($2 = 0 OR author_id = $2)
-		ORDER BY id;
+			t.closed = 0 AND
+			t.deleted = 0 AND
+			t.due <> 0 AND
+			t.due <= $1 AND
+			($2 = 0 OR t.project_id = $2)
+		ORDER BY t.priority DESC, t.due, t.id;
 	`,
-		taskID,
-		authorID,
+		time.Now().Add(soon).Unix(),
+		storage.Project(ctx),
 	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении просроченных задач: %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// queryTasks выполняет запрос, выбирающий столбцы taskColumns,
+// и возвращает найденные задачи вместе с их метками.
+func (s *Storage) queryTasks(ctx context.Context, query string, args ...interface{}) ([]model.Task, error) {
+	rows, err := s.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	var tasks []Task
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, s.loadLabels(ctx, tasks)
+}
+
+// loadLabels заполняет метки задач одним запросом к tasks_labels.
+func (s *Storage) loadLabels(ctx context.Context, tasks []model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(tasks))
+	index := make(map[int]int, len(tasks))
+	for i, t := range tasks {
+		ids = append(ids, t.ID)
+		index[t.ID] = i
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT tl.task_id, l.id, l.name
+		FROM tasks_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ANY($1)
+		ORDER BY l.id;
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении меток задач: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var l model.Label
+		if err := rows.Scan(&taskID, &l.ID, &l.Name); err != nil {
+			return fmt.Errorf("ошибка при сканировании метки: %w", dbError(err))
+		}
+		i := index[taskID]
+		tasks[i].Labels = append(tasks[i].Labels, l)
+	}
+	return rows.Err()
+}
+
+// taskFields возвращает указатели на поля задачи в порядке столбцов taskColumns.
+func taskFields(t *model.Task) []interface{} {
+	return []interface{}{
+		&t.ID,
+		epoch{&t.Opened},
+		epoch{&t.Closed},
+		&t.AuthorID,
+		&t.AssignedID,
+		&t.StatusID,
+		&t.Status,
+		&t.Priority,
+		epoch{&t.Due},
+		&t.ParentID,
+		&t.Title,
+		&t.Content,
+		&t.Version,
+		epoch{&t.Deleted},
+		&t.ProjectID,
+		&t.Rank,
+		&t.SprintID,
+	}
+}
+
+// scanTasks сканирует строки результата запроса в список задач.
+func scanTasks(rows pgx.Rows) ([]model.Task, error) {
+	defer rows.Close()
+
+	var tasks []model.Task
 	// итерирование по результату выполнения запроса
 	// и сканирование каждой строки в переменную
 	for rows.Next() {
-		var t Task
-		err = rows.Scan(
-			&t.ID,
-			&t.Opened,
-			&t.Closed,
-			&t.AuthorID,
-			&t.AssignedID,
-			&t.Title,
-			&t.Content,
-		)
+		var t model.Task
+		err := rows.Scan(taskFields(&t)...)
 		if err != nil {
 			return nil, err
 		}
@@ -128,31 +304,65 @@ func (s *Storage) Tasks(taskID, authorID int) ([]Task, error) {
 	return tasks, rows.Err()
 }
 
-// NewTask создаёт новую задачу и возвращает её id.
-func (s *Storage) NewTask(t Task, labelIDs []int) (int, error) {
+// NewTask создаёт новую задачу с метками и возвращает её id.
+// Задача и её метки сохраняются в одной транзакции.
+func (s *Storage) NewTask(ctx context.Context, t model.Task, labelIDs []int) (int, error) {
+	if err := t.Validate(); err != nil {
+		return 0, err
+	}
 	var taskID int
-	err := s.db.QueryRow(context.Background(), `
-		INSERT INTO tasks (title, content, author_id, assigned_id)
-		VALUES ($1, $2, $3, $4) RETURNING id;
+	err := s.withTx(ctx, func(tx *Storage) error {
+		var err error
+		taskID, err = tx.newTask(ctx, t, labelIDs)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return taskID, nil
+}
+
+// newTask создаёт задачу и связи с метками, вызывается внутри транзакции.
+func (s *Storage) newTask(ctx context.Context, t model.Task, labelIDs []int) (int, error) {
+	// Новая задача встаёт в конец своей колонки доски
+	projectID := storage.TaskProject(ctx, t)
+	r, err := s.lastRank(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	var taskID int
+	err = s.db.QueryRow(ctx, `
+		INSERT INTO tasks (title, content, author_id, assigned_id, priority, due, parent_id, status_id, project_id, rank)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), COALESCE(
+			NULLIF($8, 0),
+			(SELECT id FROM statuses ORDER BY position, id LIMIT 1)
+		), $9, $10) RETURNING id;
 		`,
 		t.Title,
 		t.Content,
 		t.AuthorID,
 		t.AssignedID,
+		t.Priority,
+		unix(t.Due),
+		t.ParentID,
+		t.StatusID,
+		projectID,
+		r,
 	).Scan(&taskID)
 	// return taskID , err
 	if err != nil {
-		return 0, fmt.Errorf("ошибка при создании задачи: %w", err)
+		return 0, fmt.Errorf("ошибка при создании задачи: %w", dbError(err))
+	}
+
+	if err := s.record(ctx, model.Change{TaskID: taskID, Action: model.ActionCreate, After: t.Title}); err != nil {
+		return 0, err
 	}
 
 	// 2. Добавляем связи с метками в tasks_labels
 	for _, labelID := range labelIDs {
-		_, err := s.db.Exec(context.Background(), `
-			INSERT INTO tasks_labels (task_id, label_id)
-			VALUES ($1, $2);
-		`, taskID, labelID)
-		if err != nil {
-			return 0, fmt.Errorf("ошибка при добавлении метки: %w", err)
+		if err := s.AttachLabel(ctx, taskID, labelID); err != nil {
+			return 0, err
 		}
 	}
 
@@ -161,148 +371,417 @@ func (s *Storage) NewTask(t Task, labelIDs []int) (int, error) {
 }
 
 // UpdateTask обновляет задачу по id.
-func (s *Storage) UpdateTask(t Task) error {
-	_, err := s.db.Exec(context.Background(), `
+// Смена статуса допускается только по разрешённому переходу,
+// иначе возвращается *model.TransitionError. Задачу нельзя вложить
+// в саму себя или в свою подзадачу - возвращается model.ErrParentCycle.
+// Если задачи нет, возвращается model.ErrNotFound.
+// Версия t.Version должна совпадать с текущей версией задачи, иначе
+// задачу уже изменили и возвращается model.ErrVersionConflict.
+// При переносе в другой проект задача убирается из спринта.
+// Проверки и обновление выполняются в одной транзакции.
+func (s *Storage) UpdateTask(ctx context.Context, t model.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return s.withTx(ctx, func(tx *Storage) error {
+		return tx.updateTask(ctx, t)
+	})
+}
+
+// updateTask проверяет и обновляет задачу, вызывается внутри транзакции.
+func (s *Storage) updateTask(ctx context.Context, t model.Task) error {
+	if t.ParentID != 0 {
+		var cycle bool
+		err := s.db.QueryRow(ctx, `
+			WITH RECURSIVE ancestors (id, parent_id) AS (
+				SELECT id, parent_id FROM tasks WHERE id = $1
+				UNION
+				SELECT t.id, t.parent_id
+				FROM tasks t
+				JOIN ancestors a ON t.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2);
+		`, t.ParentID, t.ID).Scan(&cycle)
+		if err != nil {
+			return fmt.Errorf("ошибка при проверке родительской задачи: %w", dbError(err))
+		}
+		if cycle {
+			return model.ErrParentCycle
+		}
+	}
+
+	// Задача до изменения, её строка блокируется до конца транзакции
+	before, err := s.lockTask(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if before.Version != t.Version {
+		return fmt.Errorf("задача %d: версия %d, текущая %d: %w", t.ID, t.Version, before.Version, model.ErrVersionConflict)
+	}
+
+	// При смене статуса задача встаёт в конец новой колонки доски
+	var newRank string
+	if t.StatusID != 0 && before.StatusID != t.StatusID {
+		if err := s.checkTransition(ctx, t.ID, before.StatusID, t.StatusID); err != nil {
+			return err
+		}
+		projectID := before.ProjectID
+		if t.ProjectID != 0 {
+			projectID = t.ProjectID
+		}
+		if newRank, err = s.lastRank(ctx, projectID); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.db.Exec(ctx, `
 		UPDATE tasks 
-		SET title = $1, content = $2, author_id = $3, assigned_id = $4
-		WHERE id = $5;
+		SET title = $1, content = $2, author_id = $3, assigned_id = $4,
+			priority = $5, due = $6, parent_id = NULLIF($7, 0),
+			status_id = COALESCE(NULLIF($8, 0), status_id),
+			project_id = COALESCE(NULLIF($10, 0), project_id),
+			sprint_id = CASE WHEN $10 IN (0, project_id) THEN sprint_id END,
+			rank = COALESCE(NULLIF($11, ''), rank),
+			version = version + 1
+		WHERE id = $9;
 		`,
 		t.Title,
 		t.Content,
 		t.AuthorID,
 		t.AssignedID,
-		t.ID)
+		t.Priority,
+		unix(t.Due),
+		t.ParentID,
+		t.StatusID,
+		t.ID,
+		t.ProjectID,
+		newRank)
 
 	if err != nil {
-		return fmt.Errorf("ошибка при обновлении задачи: %w", err)
+		return fmt.Errorf("ошибка при обновлении задачи: %w", dbError(err))
 	}
-	return nil
+
+	after, err := s.lockTask(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	return s.record(ctx, model.Diff(before, after)...)
 }
 
-// DeleteTask удаляет задачу по id.
-func (s *Storage) DeleteTask(taskID int) error {
-	_, err := s.db.Exec(context.Background(), `
-		DELETE FROM tasks WHERE id = $1;
+// DeleteTask перемещает задачу в корзину.
+// Задача со связями сохраняется и может быть восстановлена RestoreTask,
+// навсегда задачи удаляет PurgeTasks.
+// Если задачи нет или она уже в корзине, возвращается model.ErrNotFound.
+func (s *Storage) DeleteTask(ctx context.Context, taskID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		task, err := tx.lockTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if err := tx.record(ctx, model.Change{TaskID: taskID, Action: model.ActionDelete, Before: task.Title}); err != nil {
+			return err
+		}
+
+		_, err = tx.db.Exec(ctx, `
+			UPDATE tasks SET deleted = extract(epoch from now()), version = version + 1
+			WHERE id = $1;
+		`, taskID)
+		if err != nil {
+			return fmt.Errorf("ошибка при удалении задачи: %w", dbError(err))
+		}
+		return nil
+	})
+}
+
+// CloseTask закрывает задачу по id, проставляя время выполнения,
+// и возвращает задачи, которые после этого больше ничем не заблокированы.
+// Если задачи нет, возвращается model.ErrNotFound.
+func (s *Storage) CloseTask(ctx context.Context, taskID int) ([]model.Task, error) {
+	var tasks []model.Task
+	err := s.withTx(ctx, func(tx *Storage) error {
+		var err error
+		tasks, err = tx.closeTask(ctx, taskID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// closeTask закрывает задачу, вызывается внутри транзакции.
+func (s *Storage) closeTask(ctx context.Context, taskID int) ([]model.Task, error) {
+	task, err := s.lockTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsClosed() {
+		if err := s.record(ctx, model.Change{TaskID: taskID, Action: model.ActionClose}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Уже закрытая задача сохраняет исходное время выполнения
+	tag, err := s.db.Exec(ctx, `
+		UPDATE tasks
+		SET closed = CASE WHEN closed = 0 THEN extract(epoch from now()) ELSE closed END,
+			version = CASE WHEN closed = 0 THEN version + 1 ELSE version END
+		WHERE id = $1;
 	`, taskID)
 
 	if err != nil {
-		return fmt.Errorf("ошибка при удалении задачи: %w", err)
+		return nil, fmt.Errorf("ошибка при закрытии задачи: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, fmt.Errorf("задача %d: %w", taskID, model.ErrNotFound)
 	}
 
-	fmt.Printf("Задача с ID %d успешно удалена!\n", taskID)
-	return nil
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE
+			t.closed = 0 AND
+			t.deleted = 0 AND
+			t.id IN (SELECT task_id FROM task_dependencies WHERE blocker_id = $1) AND
+			`+unblocked+`
+		ORDER BY t.id;
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении разблокированных задач: %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// ReopenTask снова открывает задачу по id, сбрасывая время выполнения.
+// Если задачи нет, возвращается model.ErrNotFound.
+func (s *Storage) ReopenTask(ctx context.Context, taskID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		task, err := tx.lockTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if !task.IsClosed() {
+			return nil
+		}
+
+		_, err = tx.db.Exec(ctx, `
+			UPDATE tasks SET closed = 0, version = version + 1
+			WHERE id = $1;
+		`, taskID)
+		if err != nil {
+			return fmt.Errorf("ошибка при переоткрытии задачи: %w", dbError(err))
+		}
+		return tx.record(ctx, model.Change{TaskID: taskID, Action: model.ActionReopen})
+	})
 }
 
 // Labels возвращает список меток из БД.
-func (s *Storage) Labels() ([]Label, error) {
-	rows, err := s.db.Query(context.Background(), `
+func (s *Storage) Labels(ctx context.Context) ([]model.Label, error) {
+	rows, err := s.db.Query(ctx, `
 		SELECT id, name FROM labels ORDER BY id;
 	`)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при получении меток: %w", err)
+		return nil, fmt.Errorf("ошибка при получении меток: %w", dbError(err))
 	}
 	defer rows.Close()
 
-	var labels []Label
+	var labels []model.Label
 
 	for rows.Next() {
-		var l Label
+		var l model.Label
 		if err := rows.Scan(&l.ID, &l.Name); err != nil {
-			return nil, fmt.Errorf("ошибка при сканировании метки: %w", err)
+			return nil, fmt.Errorf("ошибка при сканировании метки: %w", dbError(err))
 		}
 		labels = append(labels, l)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при обработке строк: %w", err)
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
 	}
 
 	return labels, nil
 }
 
 // NewLabel создает новую метку и возвращает её id.
-func (s *Storage) NewLabel(l Label) (int, error) {
+func (s *Storage) NewLabel(ctx context.Context, l model.Label) (int, error) {
+	if err := l.Validate(); err != nil {
+		return 0, err
+	}
 	var id int
-	err := s.db.QueryRow(context.Background(), `
+	err := s.db.QueryRow(ctx, `
 		INSERT INTO labels (name)
 		VALUES ($1)
 		RETURNING id;
 	`, l.Name).Scan(&id)
 
 	if err != nil {
-		return 0, fmt.Errorf("ошибка при создании метки: %w", err)
+		return 0, fmt.Errorf("ошибка при создании метки: %w", dbError(err))
 	}
 	return id, nil
 }
 
+// UpdateLabel переименовывает метку.
+func (s *Storage) UpdateLabel(ctx context.Context, l model.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, `
+		UPDATE labels SET name = $1 WHERE id = $2;
+	`, l.Name, l.ID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при переименовании метки: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("метка %d: %w", l.ID, model.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteLabel удаляет метку по id, снимая её со всех задач.
+// Снятие метки записывается в историю каждой задачи.
+func (s *Storage) DeleteLabel(ctx context.Context, labelID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		rows, err := tx.db.Query(ctx, `
+			SELECT tl.task_id, l.name
+			FROM tasks_labels tl
+			JOIN labels l ON l.id = tl.label_id
+			WHERE tl.label_id = $1;
+		`, labelID)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении задач с меткой: %w", dbError(err))
+		}
+		var changes []model.Change
+		for rows.Next() {
+			c := model.Change{Action: model.ActionUnlabel}
+			if err := rows.Scan(&c.TaskID, &c.Before); err != nil {
+				rows.Close()
+				return fmt.Errorf("ошибка при сканировании задачи с меткой: %w", dbError(err))
+			}
+			changes = append(changes, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+		}
+		if err := tx.record(ctx, changes...); err != nil {
+			return err
+		}
+
+		tag, err := tx.db.Exec(ctx, `
+			DELETE FROM labels WHERE id = $1;
+		`, labelID)
+		if err != nil {
+			return fmt.Errorf("ошибка при удалении метки: %w", dbError(err))
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("метка %d: %w", labelID, model.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// AttachLabel добавляет метку к задаче.
+// Повторное добавление ничего не меняет и в историю не попадает.
+func (s *Storage) AttachLabel(ctx context.Context, taskID, labelID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		tag, err := tx.db.Exec(ctx, `
+			INSERT INTO tasks_labels (task_id, label_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING;
+		`, taskID, labelID)
+		if err != nil {
+			return fmt.Errorf("ошибка при добавлении метки: %w", dbError(err))
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+
+		var name string
+		err = tx.db.QueryRow(ctx, `
+			SELECT name FROM labels WHERE id = $1;
+		`, labelID).Scan(&name)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении метки: %w", dbError(err))
+		}
+		return tx.record(ctx, model.Change{TaskID: taskID, Action: model.ActionLabel, After: name})
+	})
+}
+
+// DetachLabel снимает метку с задачи.
+func (s *Storage) DetachLabel(ctx context.Context, taskID, labelID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		var name string
+		err := tx.db.QueryRow(ctx, `
+			DELETE FROM tasks_labels WHERE task_id = $1 AND label_id = $2
+			RETURNING (SELECT name FROM labels WHERE id = label_id);
+		`, taskID, labelID).Scan(&name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("метка %d у задачи %d: %w", labelID, taskID, model.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка при снятии метки: %w", dbError(err))
+		}
+		return tx.record(ctx, model.Change{TaskID: taskID, Action: model.ActionUnlabel, Before: name})
+	})
+}
+
 // Users возвращает список пользователей из БД.
-func (s *Storage) Users() ([]User, error) {
-	rows, err := s.db.Query(context.Background(), `
+func (s *Storage) Users(ctx context.Context) ([]model.User, error) {
+	rows, err := s.db.Query(ctx, `
 		SELECT id, name FROM users ORDER BY id;
 	`)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при получении меток: %w", err)
+		return nil, fmt.Errorf("ошибка при получении меток: %w", dbError(err))
 	}
 	defer rows.Close()
 
-	var users []User
+	var users []model.User
 
 	for rows.Next() {
-		var u User
+		var u model.User
 		if err := rows.Scan(&u.ID, &u.Name); err != nil {
-			return nil, fmt.Errorf("ошибка при сканировании пользователей: %w", err)
+			return nil, fmt.Errorf("ошибка при сканировании пользователей: %w", dbError(err))
 		}
 		users = append(users, u)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при обработке строк: %w", err)
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
 	}
 
 	return users, nil
 }
 
 // Users создает нового пользователя и возвращает его id.
-func (s *Storage) NewUser(u User) (int, error) {
+func (s *Storage) NewUser(ctx context.Context, u model.User) (int, error) {
+	if err := u.Validate(); err != nil {
+		return 0, err
+	}
 	var id int
-	err := s.db.QueryRow(context.Background(), `
+	err := s.db.QueryRow(ctx, `
 		INSERT INTO users (name)
 		VALUES ($1)
 		RETURNING id;
 	`, u.Name).Scan(&id)
 
 	if err != nil {
-		return 0, fmt.Errorf("ошибка при создании пользователя: %w", err)
+		return 0, fmt.Errorf("ошибка при создании пользователя: %w", dbError(err))
 	}
 	return id, nil
 }
 
 // GetTasksByAuthor возвращает список задач по id автора.
-func (s *Storage) GetTasksByAuthor(authorID int) ([]Task, error) {
-	rows, err := s.db.Query(context.Background(), `
-		SELECT id, title, content, author_id, assigned_id 
-		FROM tasks WHERE author_id = $1;
-	`, authorID)
+func (s *Storage) GetTasksByAuthor(ctx context.Context, authorID int) ([]model.Task, error) {
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE t.author_id = $1 AND t.deleted = 0 AND ($2 = 0 OR t.project_id = $2)
+		ORDER BY t.id;
+	`, authorID, storage.Project(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при получении задач: %w", err)
+		return nil, fmt.Errorf("ошибка при получении задач: %w", dbError(err))
 	}
-	defer rows.Close()
-
-	var tasks []Task
-
-	for rows.Next() {
-		var t Task
-		err := rows.Scan(
-			&t.ID,
-			&t.Title,
-			&t.Content,
-			&t.AuthorID,
-			&t.AssignedID,
-		)
-		if err != nil {
-			return nil, err
-		}
-		tasks = append(tasks, t)
-	}
-
-	return tasks, rows.Err()
+	return tasks, nil
 }
@@ -1,41 +1,138 @@
 package postgres
 
 import (
-	"reflect"
+	"context"
+	"os"
 	"testing"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+	"task-meneger/pkg/storage/storagetest"
 )
 
-func TestDatabaseConnection(t *testing.T) {
-	_, err := New()
+// testDatabaseEnv — переменная окружения со строкой подключения к тестовой БД.
+// Тесты удаляют и заново создают все таблицы, поэтому настройки БД приложения
+// (DATABASE_URL, DB_HOST, DB_CONFIG) для них не используются никогда.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// connect подключается к тестовой БД из TEST_DATABASE_URL.
+// Если переменная не задана, тест пропускается.
+func connect(t *testing.T) *Storage {
+	t.Helper()
+	url := os.Getenv(testDatabaseEnv)
+	if url == "" {
+		t.Skip(testDatabaseEnv + " не задана, тесты с PostgreSQL пропущены")
+	}
+	s, err := Connect(context.Background(), Config{URL: url})
 	if err != nil {
 		t.Fatalf("Ошибка подключения к БД: %v", err)
 	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestDatabaseConnection(t *testing.T) {
+	connect(t)
+}
+
+// Перед каждым тестом все миграции откатываются и применяются заново,
+// поэтому TEST_DATABASE_URL должна указывать на отдельную тестовую БД.
+func TestStorage(t *testing.T) {
+	connect(t)
+	storagetest.Run(t, func(t *testing.T) storage.Interface {
+		s := connect(t)
+		if _, err := s.MigrateDown(context.Background(), 0); err != nil {
+			t.Fatalf("Ошибка отката миграций: %v", err)
+		}
+		if _, err := s.MigrateUp(context.Background()); err != nil {
+			t.Fatalf("Ошибка применения миграций: %v", err)
+		}
+		return s
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations() error = %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 {
+		t.Fatalf("Migrations() = %v, want начиная с версии 1", migrations)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Migrations()[%d] версия %d, want %d без пропусков", i, m.Version, i+1)
+		}
+	}
+}
+
+// БД, созданная из schema.sql первой версии, с данными этой версии,
+// после миграций работает как новая.
+func TestMigrateBaseline(t *testing.T) {
+	s := connect(t)
+	ctx := context.Background()
+	if _, err := s.MigrateDown(ctx, 0); err != nil {
+		t.Fatalf("Ошибка отката миграций: %v", err)
+	}
+	schema, err := os.ReadFile("testdata/schema_baseline.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(ctx, string(schema)); err != nil {
+		t.Fatalf("Ошибка создания схемы первой версии: %v", err)
+	}
+	// Первая версия схемы допускала повторы меток у задачи
+	_, err = s.db.Exec(ctx, `
+		INSERT INTO labels (name) VALUES ('срочно');
+		INSERT INTO tasks (title, content) VALUES ('старая задача', 'перенести данные');
+		INSERT INTO tasks_labels (task_id, label_id) SELECT t.id, l.id FROM tasks t, labels l;
+		INSERT INTO tasks_labels (task_id, label_id) SELECT t.id, l.id FROM tasks t, labels l;
+	`)
+	if err != nil {
+		t.Fatalf("Ошибка заполнения схемы первой версии: %v", err)
+	}
+	if _, err := s.MigrateUp(ctx); err != nil {
+		t.Fatalf("Ошибка применения миграций: %v", err)
+	}
+
+	tasks, err := s.Tasks(ctx, model.TaskFilter{})
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Tasks() = %v, want одну задачу", tasks)
+	}
+	task := tasks[0]
+	if task.Status != "backlog" || task.Priority != model.PriorityLow || task.ProjectID != model.DefaultProject || len(task.Labels) != 1 {
+		t.Errorf("Tasks() после миграций = %+v, want backlog, низкий приоритет, проект по умолчанию и одну метку", task)
+	}
+
+	label := task.Labels[0].ID
+	if err := s.AttachLabel(ctx, task.ID, label); err != nil {
+		t.Fatalf("AttachLabel() error = %v", err)
+	}
+	if got := getTask(t, s, task.ID); len(got.Labels) != 1 {
+		t.Errorf("AttachLabel() повторно: метки = %v, want одна", got.Labels)
+	}
+	results, err := s.SearchTasks(ctx, "данные", 10)
+	if err != nil || len(results) != 1 || results[0].Task.ID != task.ID {
+		t.Errorf("SearchTasks() = %v, %v, want задачу %d", results, err, task.ID)
+	}
+	// Метка удаляется вместе со связями с задачами
+	if err := s.DeleteLabel(ctx, label); err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+	if _, err := s.NewTask(ctx, model.Task{Title: "новая задача"}, nil); err != nil {
+		t.Errorf("NewTask() error = %v", err)
+	}
 }
 
-func TestStorage_Tasks(t *testing.T) {
-	type args struct {
-		taskID   int
-		authorID int
-	}
-	tests := []struct {
-		name    string
-		s       *Storage
-		args    args
-		want    []Task
-		wantErr bool
-	}{
-		// TODO: Add test cases.
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.s.Tasks(tt.args.taskID, tt.args.authorID)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Storage.Tasks() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Storage.Tasks() = %v, want %v", got, tt.want)
-			}
-		})
+// getTask возвращает задачу по id.
+func getTask(t *testing.T, s *Storage, id int) model.Task {
+	t.Helper()
+	tasks, err := s.Tasks(context.Background(), model.TaskFilter{TaskID: id})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("Tasks(TaskID: %d) = %v, %v, want одну задачу", id, tasks, err)
 	}
+	return tasks[0]
 }
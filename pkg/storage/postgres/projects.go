@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+)
+
+// Projects возвращает список проектов.
+func (s *Storage) Projects(ctx context.Context) ([]model.Project, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, name, description FROM projects ORDER BY id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении проектов: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var projects []model.Project
+
+	for rows.Next() {
+		var p model.Project
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании проекта: %w", dbError(err))
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return projects, nil
+}
+
+// NewProject создаёт проект и возвращает его id.
+func (s *Storage) NewProject(ctx context.Context, p model.Project) (int, error) {
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO projects (name, description)
+		VALUES ($1, $2)
+		RETURNING id;
+	`, p.Name, p.Description).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при создании проекта: %w", dbError(err))
+	}
+	return id, nil
+}
+
+// UpdateProject изменяет название и описание проекта.
+// Если проекта нет, возвращается model.ErrNotFound.
+func (s *Storage) UpdateProject(ctx context.Context, p model.Project) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, `
+		UPDATE projects SET name = $1, description = $2 WHERE id = $3;
+	`, p.Name, p.Description, p.ID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при изменении проекта: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("проект %d: %w", p.ID, model.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteProject удаляет проект без задач, в том числе без задач в корзине, и без спринтов.
+// Если в проекте есть задачи или спринты, возвращается model.ErrReferenced,
+// проект по умолчанию удалить нельзя - возвращается model.ErrConflict.
+func (s *Storage) DeleteProject(ctx context.Context, projectID int) error {
+	if projectID == model.DefaultProject {
+		return fmt.Errorf("проект %d - проект по умолчанию: %w", projectID, model.ErrConflict)
+	}
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM projects WHERE id = $1;
+	`, projectID)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении проекта: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("проект %d: %w", projectID, model.ErrNotFound)
+	}
+	return nil
+}
+
+// ProjectMembers возвращает участников проекта.
+func (s *Storage) ProjectMembers(ctx context.Context, projectID int) ([]model.User, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT u.id, u.name
+		FROM project_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.project_id = $1
+		ORDER BY u.id;
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении участников проекта: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var users []model.User
+
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.Name); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании участника проекта: %w", dbError(err))
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return users, nil
+}
+
+// AddProjectMember добавляет пользователя в участники проекта.
+// Если проекта или пользователя нет, возвращается model.ErrReferenced.
+func (s *Storage) AddProjectMember(ctx context.Context, projectID, userID int) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO project_members (project_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка при добавлении участника проекта: %w", dbError(err))
+	}
+	return nil
+}
+
+// RemoveProjectMember исключает пользователя из участников проекта.
+// Если пользователь не участвует в проекте, возвращается model.ErrNotFound.
+func (s *Storage) RemoveProjectMember(ctx context.Context, projectID, userID int) error {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM project_members WHERE project_id = $1 AND user_id = $2;
+	`, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка при исключении участника проекта: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("пользователь %d в проекте %d: %w", userID, projectID, model.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// SearchTasks выполняет полнотекстовый поиск по названию и тексту задач
+// и возвращает не более limit результатов (0 - без ограничения), начиная с самых релевантных.
+// Запрос понимает синтаксис websearch: "фраза в кавычках", or, -исключение.
+func (s *Storage) SearchTasks(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	rows, err := s.db.Query(ctx, `
+		WITH q (query) AS (
+			SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1)
+		)
+		SELECT `+taskColumns+`,
+			ts_rank(t.search, q.query)::float8 AS search_rank,
+			ts_headline(
+				'russian',
+				coalesce(t.title, '') || ' — ' || coalesce(t.content, ''),
+				q.query,
+				'StartSel=«, StopSel=», MaxWords=25, MinWords=8, MaxFragments=2'
+			)
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		CROSS JOIN q
+		WHERE t.search @@ q.query AND t.deleted = 0 AND ($3 = 0 OR t.project_id = $3)
+		ORDER BY search_rank DESC, t.id
+		LIMIT NULLIF($2, 0);
+	`, query, limit, storage.Project(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске задач: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var results []model.SearchResult
+	for rows.Next() {
+		var r model.SearchResult
+		fields := append(taskFields(&r.Task), &r.Rank, &r.Snippet)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании результата поиска: %w", dbError(err))
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	// Метки загружаются для найденных задач одним запросом
+	tasks := make([]model.Task, len(results))
+	for i, r := range results {
+		tasks[i] = r.Task
+	}
+	if err := s.loadLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Task = tasks[i]
+	}
+
+	return results, nil
+}
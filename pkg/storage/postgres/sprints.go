@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Sprints возвращает спринты проекта из контекста в порядке начала.
+func (s *Storage) Sprints(ctx context.Context) ([]model.Sprint, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, project_id, name, starts, ends FROM sprints
+		WHERE $1 = 0 OR project_id = $1
+		ORDER BY starts, id;
+	`, storage.Project(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении спринтов: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var sprints []model.Sprint
+
+	for rows.Next() {
+		var sp model.Sprint
+		if err := rows.Scan(&sp.ID, &sp.ProjectID, &sp.Name, epoch{&sp.Start}, epoch{&sp.End}); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании спринта: %w", dbError(err))
+		}
+		sprints = append(sprints, sp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return sprints, nil
+}
+
+// NewSprint создаёт спринт и возвращает его id.
+// Спринт без проекта создаётся в проекте из контекста (см. storage.SprintProject).
+func (s *Storage) NewSprint(ctx context.Context, sp model.Sprint) (int, error) {
+	if err := sp.Validate(); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO sprints (project_id, name, starts, ends)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`, storage.SprintProject(ctx, sp), sp.Name, unix(sp.Start), unix(sp.End)).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при создании спринта: %w", dbError(err))
+	}
+	return id, nil
+}
+
+// AddToSprint планирует задачу в спринт, задача из другого спринта переносится.
+// Если спринта нет, возвращается model.ErrReferenced, если спринт
+// из другого проекта - model.ErrInvalid.
+func (s *Storage) AddToSprint(ctx context.Context, sprintID, taskID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		task, err := tx.lockTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		sp, err := tx.sprint(ctx, sprintID)
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("спринт %d: %w", sprintID, model.ErrReferenced)
+		}
+		if err != nil {
+			return err
+		}
+		if sp.ProjectID != task.ProjectID {
+			return fmt.Errorf("%w: спринт %d из другого проекта, чем задача %d", model.ErrInvalid, sprintID, taskID)
+		}
+		if task.SprintID == sprintID {
+			return nil
+		}
+		return tx.setSprint(ctx, task, sprintID)
+	})
+}
+
+// RemoveFromSprint убирает задачу из спринта.
+// Если задача не запланирована в этот спринт, возвращается model.ErrNotFound.
+func (s *Storage) RemoveFromSprint(ctx context.Context, sprintID, taskID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		task, err := tx.lockTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if task.SprintID != sprintID {
+			return fmt.Errorf("задача %d в спринте %d: %w", taskID, sprintID, model.ErrNotFound)
+		}
+		return tx.setSprint(ctx, task, 0)
+	})
+}
+
+// CarryOver переносит незакрытые задачи спринта fromID в спринт toID
+// и возвращает число перенесённых задач. Задачи в корзине остаются в спринте.
+// Если спринта fromID нет, возвращается model.ErrNotFound, если нет спринта toID -
+// model.ErrReferenced, спринты должны быть разными и из одного проекта.
+func (s *Storage) CarryOver(ctx context.Context, fromID, toID int) (int, error) {
+	var moved int
+	err := s.withTx(ctx, func(tx *Storage) error {
+		from, err := tx.sprint(ctx, fromID)
+		if err != nil {
+			return err
+		}
+		to, err := tx.sprint(ctx, toID)
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("спринт %d: %w", toID, model.ErrReferenced)
+		}
+		if err != nil {
+			return err
+		}
+		if fromID == toID || from.ProjectID != to.ProjectID {
+			return fmt.Errorf("%w: задачи переносятся в другой спринт того же проекта", model.ErrInvalid)
+		}
+
+		tasks, err := tx.queryTasks(ctx, `
+			SELECT `+taskColumns+`
+			FROM tasks t
+			JOIN statuses s ON s.id = t.status_id
+			WHERE t.sprint_id = $1 AND t.closed = 0 AND t.deleted = 0
+			ORDER BY t.id
+			FOR UPDATE OF t;
+		`, fromID)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении задач спринта: %w", dbError(err))
+		}
+		for _, t := range tasks {
+			if err := tx.setSprint(ctx, t, toID); err != nil {
+				return err
+			}
+		}
+		moved = len(tasks)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// SprintSummary подводит итоги спринта по истории задач, которые
+// когда-либо планировались в него (см. model.SummarizeSprint).
+// Если спринта нет, возвращается model.ErrNotFound.
+func (s *Storage) SprintSummary(ctx context.Context, sprintID int) (model.SprintSummary, error) {
+	sp, err := s.sprint(ctx, sprintID)
+	if err != nil {
+		return model.SprintSummary{}, err
+	}
+	id := strconv.Itoa(sprintID)
+	changes, err := s.queryChanges(ctx, `
+		SELECT `+changeColumns+`
+		FROM task_history h
+		JOIN users u ON u.id = h.actor_id
+		WHERE h.task_id IN (
+			SELECT task_id FROM task_history
+			WHERE field = $1 AND (before = $2 OR after = $2)
+		)
+		ORDER BY h.at, h.id;
+	`, model.FieldSprint, id)
+	if err != nil {
+		return model.SprintSummary{}, fmt.Errorf("ошибка при получении истории задач спринта: %w", dbError(err))
+	}
+	return model.SummarizeSprint(sp, changes, time.Now()), nil
+}
+
+// sprint возвращает спринт по id.
+// Если спринта нет, возвращается model.ErrNotFound.
+func (s *Storage) sprint(ctx context.Context, sprintID int) (model.Sprint, error) {
+	var sp model.Sprint
+	err := s.db.QueryRow(ctx, `
+		SELECT id, project_id, name, starts, ends FROM sprints WHERE id = $1;
+	`, sprintID).Scan(&sp.ID, &sp.ProjectID, &sp.Name, epoch{&sp.Start}, epoch{&sp.End})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sp, fmt.Errorf("спринт %d: %w", sprintID, model.ErrNotFound)
+	}
+	if err != nil {
+		return sp, fmt.Errorf("ошибка при получении спринта %d: %w", sprintID, dbError(err))
+	}
+	return sp, nil
+}
+
+// setSprint переносит задачу в спринт sprintID, 0 - убирает из спринта,
+// и записывает изменение в историю. Строка задачи должна быть заблокирована.
+func (s *Storage) setSprint(ctx context.Context, task model.Task, sprintID int) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE tasks SET sprint_id = NULLIF($1, 0), version = version + 1
+		WHERE id = $2;
+	`, sprintID, task.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка при планировании задачи %d: %w", task.ID, dbError(err))
+	}
+	after := task
+	after.SprintID = sprintID
+	return s.record(ctx, model.Diff(task, after)...)
+}
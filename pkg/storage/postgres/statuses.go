@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+)
+
+// Statuses возвращает список статусов в порядке процесса работы.
+func (s *Storage) Statuses(ctx context.Context) ([]model.Status, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, name, position FROM statuses ORDER BY position, id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении статусов: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var statuses []model.Status
+
+	for rows.Next() {
+		var st model.Status
+		if err := rows.Scan(&st.ID, &st.Name, &st.Position); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании статуса: %w", dbError(err))
+		}
+		statuses = append(statuses, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return statuses, nil
+}
+
+// NewStatus создаёт новый статус и возвращает его id.
+func (s *Storage) NewStatus(ctx context.Context, st model.Status) (int, error) {
+	if err := st.Validate(); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO statuses (name, position)
+		VALUES ($1, $2)
+		RETURNING id;
+	`, st.Name, st.Position).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при создании статуса: %w", dbError(err))
+	}
+	return id, nil
+}
+
+// checkTransition проверяет, разрешён ли переход задачи taskID между статусами.
+// Если переход запрещён, возвращается *model.TransitionError.
+func (s *Storage) checkTransition(ctx context.Context, taskID, fromID, toID int) error {
+	var allowed bool
+	err := s.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM status_transitions
+			WHERE from_id = $1 AND to_id = $2
+		);
+	`, fromID, toID).Scan(&allowed)
+	if err != nil {
+		return fmt.Errorf("ошибка при проверке перехода: %w", dbError(err))
+	}
+	if !allowed {
+		return &model.TransitionError{TaskID: taskID, FromID: fromID, ToID: toID}
+	}
+	return nil
+}
+
+// Transitions возвращает все разрешённые переходы между статусами.
+func (s *Storage) Transitions(ctx context.Context) ([]model.Transition, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT from_id, to_id FROM status_transitions ORDER BY from_id, to_id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении переходов: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var transitions []model.Transition
+
+	for rows.Next() {
+		var tr model.Transition
+		if err := rows.Scan(&tr.FromID, &tr.ToID); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании перехода: %w", dbError(err))
+		}
+		transitions = append(transitions, tr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return transitions, nil
+}
+
+// AddTransition разрешает переход из одного статуса в другой.
+func (s *Storage) AddTransition(ctx context.Context, fromID, toID int) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO status_transitions (from_id, to_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`, fromID, toID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при добавлении перехода: %w", dbError(err))
+	}
+	return nil
+}
+
+// DeleteTransition запрещает переход из одного статуса в другой.
+func (s *Storage) DeleteTransition(ctx context.Context, fromID, toID int) error {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM status_transitions WHERE from_id = $1 AND to_id = $2;
+	`, fromID, toID)
+
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении перехода: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("переход из статуса %d в статус %d: %w", fromID, toID, model.ErrNotFound)
+	}
+	return nil
+}
+
+// NextStatuses возвращает статусы, в которые можно перевести задачу из данного статуса.
+func (s *Storage) NextStatuses(ctx context.Context, statusID int) ([]model.Status, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT s.id, s.name, s.position
+		FROM status_transitions tr
+		JOIN statuses s ON s.id = tr.to_id
+		WHERE tr.from_id = $1
+		ORDER BY s.position, s.id;
+	`, statusID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении статусов: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var statuses []model.Status
+
+	for rows.Next() {
+		var st model.Status
+		if err := rows.Scan(&st.ID, &st.Name, &st.Position); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании статуса: %w", dbError(err))
+		}
+		statuses = append(statuses, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// RestoreTask восстанавливает задачу из корзины.
+// Если в корзине нет такой задачи, возвращается model.ErrNotFound.
+func (s *Storage) RestoreTask(ctx context.Context, taskID int) error {
+	return s.withTx(ctx, func(tx *Storage) error {
+		tag, err := tx.db.Exec(ctx, `
+			UPDATE tasks SET deleted = 0, version = version + 1
+			WHERE id = $1 AND deleted <> 0;
+		`, taskID)
+		if err != nil {
+			return fmt.Errorf("ошибка при восстановлении задачи: %w", dbError(err))
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("задача %d в корзине: %w", taskID, model.ErrNotFound)
+		}
+		return tx.record(ctx, model.Change{TaskID: taskID, Action: model.ActionRestore})
+	})
+}
+
+// PurgeTasks навсегда удаляет задачи проекта из контекста, перемещённые в корзину не позже before,
+// вместе с их метками, зависимостями и комментариями. Подзадачи удалённых задач
+// становятся задачами верхнего уровня. История задач сохраняется.
+// Возвращает число удалённых задач.
+func (s *Storage) PurgeTasks(ctx context.Context, before time.Time) (int, error) {
+	var purged int
+	err := s.withTx(ctx, func(tx *Storage) error {
+		rows, err := tx.db.Query(ctx, `
+			SELECT id, title FROM tasks
+			WHERE deleted <> 0 AND deleted <= $1 AND ($2 = 0 OR project_id = $2)
+			ORDER BY id
+			FOR UPDATE;
+		`, before.Unix(), storage.Project(ctx))
+		if err != nil {
+			return fmt.Errorf("ошибка при получении задач в корзине: %w", dbError(err))
+		}
+		var ids []int
+		var changes []model.Change
+		for rows.Next() {
+			c := model.Change{Action: model.ActionPurge}
+			if err := rows.Scan(&c.TaskID, &c.Before); err != nil {
+				rows.Close()
+				return fmt.Errorf("ошибка при сканировании задачи в корзине: %w", dbError(err))
+			}
+			ids = append(ids, c.TaskID)
+			changes = append(changes, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("ошибка при обработке строк: %w", dbError(err))
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// Подзадачи, которые остаются, теряют родителя, как ON DELETE SET NULL
+		children, err := tx.queryTasks(ctx, `
+			SELECT `+taskColumns+`
+			FROM tasks t
+			JOIN statuses s ON s.id = t.status_id
+			WHERE t.parent_id = ANY($1) AND NOT t.id = ANY($1)
+			ORDER BY t.id;
+		`, ids)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении подзадач: %w", dbError(err))
+		}
+		for _, child := range children {
+			orphan := child
+			orphan.ParentID = 0
+			changes = append(changes, model.Diff(child, orphan)...)
+		}
+		if err := tx.record(ctx, changes...); err != nil {
+			return err
+		}
+
+		tag, err := tx.db.Exec(ctx, `
+			DELETE FROM tasks WHERE id = ANY($1);
+		`, ids)
+		if err != nil {
+			return fmt.Errorf("ошибка при удалении задач: %w", dbError(err))
+		}
+		purged = int(tag.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Subtasks возвращает прямые подзадачи задачи.
+func (s *Storage) Subtasks(ctx context.Context, parentID int) ([]model.Task, error) {
+	tasks, err := s.queryTasks(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE t.parent_id = $1 AND t.deleted = 0
+		ORDER BY t.id;
+	`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении подзадач: %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// TaskTree возвращает дерево задачи со всеми её подзадачами.
+// При rootID = 0 возвращается лес всех задач проекта из контекста.
+// Задачи в корзине и в других проектах в дерево не входят,
+// их подзадачи становятся корнями.
+func (s *Storage) TaskTree(ctx context.Context, rootID int) ([]*model.TaskNode, error) {
+	tasks, err := s.queryTasks(ctx, `
+		WITH RECURSIVE tree (id) AS (
+			SELECT id FROM tasks
+			WHERE deleted = 0 AND ($2 = 0 OR project_id = $2) AND (
+				($1 = 0 AND (parent_id IS NULL OR parent_id IN (
+					SELECT id FROM tasks WHERE deleted <> 0 OR ($2 <> 0 AND project_id <> $2)
+				))) OR
+				id = $1
+			)
+			UNION
+			SELECT t.id
+			FROM tasks t
+			JOIN tree ON t.parent_id = tree.id
+			WHERE t.deleted = 0 AND ($2 = 0 OR t.project_id = $2)
+		)
+		SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN statuses s ON s.id = t.status_id
+		WHERE t.id IN (SELECT id FROM tree)
+		ORDER BY t.id;
+	`, rootID, storage.Project(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении дерева задач: %w", dbError(err))
+	}
+	return model.BuildTree(tasks), nil
+}
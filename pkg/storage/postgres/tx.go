@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"task-meneger/pkg/storage"
+)
+
+// querier — общие методы пула соединений и транзакции,
+// через которые хранилище выполняет запросы.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// WithTx выполняет fn как единое целое: если fn возвращает ошибку,
+// все изменения откатываются, иначе фиксируются.
+// Внутри fn все операции нужно выполнять через переданное хранилище tx.
+// Вложенный вызов WithTx создаёт точку сохранения в текущей транзакции.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx storage.Interface) error) error {
+	return s.withTx(ctx, func(tx *Storage) error { return fn(tx) })
+}
+
+// withTx — WithTx для методов хранилища: fn получает *Storage
+// и может выполнять запросы через tx.db напрямую.
+func (s *Storage) withTx(ctx context.Context, fn func(tx *Storage) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка при начале транзакции: %w", err)
+	}
+	// после Commit откат ничего не делает
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Storage{pool: s.pool, db: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка при фиксации транзакции: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+
+	"task-meneger/pkg/model"
+)
+
+type projectKey struct{}
+
+// WithProject возвращает контекст, в котором работа ведётся с проектом projectID:
+// списки задач, поиск и отчёты ограничиваются этим проектом,
+// новые задачи без указанного проекта создаются в нём.
+func WithProject(ctx context.Context, projectID int) context.Context {
+	return context.WithValue(ctx, projectKey{}, projectID)
+}
+
+// Project возвращает проект из контекста, 0 - задачи всех проектов.
+func Project(ctx context.Context) int {
+	id, _ := ctx.Value(projectKey{}).(int)
+	return id
+}
+
+// TaskProject возвращает проект новой задачи: указанный в задаче,
+// иначе проект из контекста, иначе model.DefaultProject.
+func TaskProject(ctx context.Context, t model.Task) int {
+	return newProject(ctx, t.ProjectID)
+}
+
+// SprintProject возвращает проект нового спринта по тем же правилам, что и TaskProject.
+func SprintProject(ctx context.Context, s model.Sprint) int {
+	return newProject(ctx, s.ProjectID)
+}
+
+// newProject возвращает projectID, иначе проект из контекста, иначе model.DefaultProject.
+func newProject(ctx context.Context, projectID int) int {
+	switch {
+	case projectID != 0:
+		return projectID
+	case Project(ctx) != 0:
+		return Project(ctx)
+	}
+	return model.DefaultProject
+}
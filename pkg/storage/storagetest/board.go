@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+func testBoard(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	a := mustTask(t, s, model.Task{Title: "a"})
+	b := mustTask(t, s, model.Task{Title: "b"})
+	c := mustTask(t, s, model.Task{Title: "c"})
+	trashed := mustTask(t, s, model.Task{Title: "в корзине"})
+	if err := s.DeleteTask(ctx, trashed); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	wantColumns(t, s, "Board()", [][]int{{a, b, c}, nil, nil, nil})
+
+	move := func(taskID, statusID, afterID int) {
+		t.Helper()
+		if err := s.MoveTask(ctx, taskID, statusID, afterID); err != nil {
+			t.Fatalf("MoveTask(%d, %d, %d) error = %v", taskID, statusID, afterID, err)
+		}
+	}
+	move(c, 0, 0)
+	wantColumns(t, s, "Board() после переноса в начало", [][]int{{c, a, b}, nil, nil, nil})
+	move(c, 0, a)
+	wantColumns(t, s, "Board() после переноса в середину", [][]int{{a, c, b}, nil, nil, nil})
+	move(a, statusInProgress, 0)
+	move(b, statusInProgress, a)
+	move(c, statusInProgress, a)
+	wantColumns(t, s, "Board() после смены колонки", [][]int{nil, {a, c, b}, nil, nil})
+
+	// Смена колонки - это смена статуса: версия растёт, изменение попадает в историю
+	task := getTask(t, s, a)
+	if task.Status != "in progress" || task.Version != 2 {
+		t.Errorf("MoveTask() статус = %q, версия = %d, want in progress и 2", task.Status, task.Version)
+	}
+	changes, err := s.History(ctx, a)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if last := changes[len(changes)-1]; last.Field != model.FieldStatus || last.After != "in progress" {
+		t.Errorf("History() после MoveTask() последняя запись = %+v", last)
+	}
+
+	// Многократные вставки в одно место сохраняют порядок
+	for i := 0; i < 30; i++ {
+		move(b, 0, a)
+		move(c, 0, a)
+	}
+	wantColumns(t, s, "Board() после перестановок", [][]int{nil, {a, c, b}, nil, nil})
+
+	// При смене статуса через UpdateTask задача встаёт в конец колонки
+	task = getTask(t, s, a)
+	task.StatusID = statusBacklog
+	if err := s.UpdateTask(ctx, task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	d := mustTask(t, s, model.Task{Title: "d"})
+	wantColumns(t, s, "Board() после UpdateTask()", [][]int{{a, d}, {c, b}, nil, nil})
+
+	err = s.MoveTask(ctx, d, statusDone, 0)
+	wantErr(t, "MoveTask() запрещённым переходом", err, storage.ErrConflict)
+	wantErr(t, "MoveTask() после задачи из другой колонки", s.MoveTask(ctx, d, 0, c), storage.ErrInvalid)
+	wantErr(t, "MoveTask() после самой себя", s.MoveTask(ctx, d, 0, d), storage.ErrInvalid)
+	wantErr(t, "MoveTask() после неизвестной задачи", s.MoveTask(ctx, d, 0, 999), storage.ErrNotFound)
+	wantErr(t, "MoveTask() неизвестной задачи", s.MoveTask(ctx, 999, 0, 0), storage.ErrNotFound)
+	wantErr(t, "MoveTask() задачи в корзине", s.MoveTask(ctx, trashed, 0, 0), storage.ErrNotFound)
+
+	// Задачи ранжируются только среди задач своего проекта
+	other := mustTask(t, s, model.Task{ProjectID: mustProject(t, s, "web"), Title: "сайт"})
+	wantErr(t, "MoveTask() после задачи другого проекта", s.MoveTask(ctx, d, 0, other), storage.ErrInvalid)
+	wantErr(t, "MoveTask() задачи другого проекта после задачи", s.MoveTask(ctx, other, 0, a), storage.ErrInvalid)
+}
+
+// wantColumns проверяет id задач в колонках доски по порядку статусов.
+func wantColumns(t *testing.T, s storage.Interface, op string, want [][]int) {
+	t.Helper()
+	columns, err := s.Board(context.Background())
+	if err != nil {
+		t.Fatalf("%s error = %v", op, err)
+	}
+	if len(columns) != len(want) {
+		t.Fatalf("%s колонок = %d, want %d", op, len(columns), len(want))
+	}
+	for i, col := range columns {
+		if got := ids(col.Tasks); !equalIDs(got, want[i]) {
+			t.Errorf("%s колонка %q = %v, want %v", op, col.Status.Name, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+func testHistory(t *testing.T, s storage.Interface) {
+	alice := mustUser(t, s, "alice")
+	bob := mustUser(t, s, "bob")
+	ctx := storage.WithActor(context.Background(), alice)
+
+	bug := mustLabel(t, s, "bug")
+	parent, err := s.NewTask(ctx, model.Task{Title: "старый", AssignedID: alice}, []int{bug})
+	if err != nil {
+		t.Fatalf("NewTask() error = %v", err)
+	}
+	child := mustTask(t, s, model.Task{ParentID: parent})
+
+	task := getTask(t, s, parent)
+	task.Title = "новый"
+	task.AssignedID = bob
+	task.StatusID = statusInProgress
+	if err := s.UpdateTask(storage.WithActor(ctx, bob), task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := s.CloseTask(ctx, parent); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	if _, err := s.CloseTask(ctx, parent); err != nil {
+		t.Fatalf("CloseTask() повторно error = %v", err)
+	}
+	if err := s.ReopenTask(ctx, parent); err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	if err := s.AttachLabel(ctx, parent, bug); err != nil {
+		t.Fatalf("AttachLabel() повторно error = %v", err)
+	}
+	if err := s.DetachLabel(ctx, parent, bug); err != nil {
+		t.Fatalf("DetachLabel() error = %v", err)
+	}
+	if err := s.DeleteTask(ctx, parent); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if err := s.RestoreTask(storage.WithActor(ctx, bob), parent); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if err := s.DeleteTask(ctx, parent); err != nil {
+		t.Fatalf("DeleteTask() повторно error = %v", err)
+	}
+	if _, err := s.PurgeTasks(ctx, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("PurgeTasks() error = %v", err)
+	}
+
+	// История удалённой задачи сохраняется
+	changes, err := s.History(ctx, parent)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	want := []model.Change{
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionCreate, After: "старый"},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionLabel, After: "bug"},
+		{Actor: model.User{ID: bob, Name: "bob"}, Action: model.ActionUpdate, Field: model.FieldTitle, Before: "старый", After: "новый"},
+		{Actor: model.User{ID: bob, Name: "bob"}, Action: model.ActionUpdate, Field: model.FieldAssignee, Before: strconv.Itoa(alice), After: strconv.Itoa(bob)},
+		{Actor: model.User{ID: bob, Name: "bob"}, Action: model.ActionUpdate, Field: model.FieldStatus, Before: "backlog", After: "in progress"},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionClose},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionReopen},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionUnlabel, Before: "bug"},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionDelete, Before: "новый"},
+		{Actor: model.User{ID: bob, Name: "bob"}, Action: model.ActionRestore},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionDelete, Before: "новый"},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionPurge, Before: "новый"},
+	}
+	wantChanges(t, "History()", changes, want)
+
+	// Подзадача окончательно удалённой задачи становится задачей верхнего уровня
+	changes, err = s.History(ctx, child)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	wantChanges(t, "History() подзадачи", changes, []model.Change{
+		{Actor: model.User{ID: 0, Name: "default"}, Action: model.ActionCreate, After: "задача"},
+		{Actor: model.User{ID: alice, Name: "alice"}, Action: model.ActionUpdate, Field: model.FieldParent, Before: strconv.Itoa(parent), After: "0"},
+	})
+
+	// Неизвестный пользователь ничего не меняет
+	ghost := storage.WithActor(context.Background(), 999)
+	_, err = s.NewTask(ghost, model.Task{Title: "a"}, nil)
+	wantErr(t, "NewTask() от неизвестного пользователя", err, storage.ErrReferenced)
+	wantErr(t, "ReopenTask() закрытой задачи от неизвестного пользователя", closeAndReopen(t, s, ghost, child), storage.ErrReferenced)
+	if !getTask(t, s, child).IsClosed() {
+		t.Errorf("ReopenTask() от неизвестного пользователя открыл задачу")
+	}
+
+	// Удаление метки снимает её с задач и попадает в их историю
+	if err := s.AttachLabel(ctx, child, bug); err != nil {
+		t.Fatalf("AttachLabel() error = %v", err)
+	}
+	if err := s.DeleteLabel(ctx, bug); err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+	changes, _ = s.History(ctx, child)
+	if last := changes[len(changes)-1]; last.Action != model.ActionUnlabel || last.Before != "bug" {
+		t.Errorf("History() после DeleteLabel() последняя запись = %+v", last)
+	}
+}
+
+// closeAndReopen закрывает задачу и переоткрывает её в контексте ctx.
+func closeAndReopen(t *testing.T, s storage.Interface, ctx context.Context, id int) error {
+	t.Helper()
+	if _, err := s.CloseTask(context.Background(), id); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	return s.ReopenTask(ctx, id)
+}
+
+// wantChanges сравнивает записи истории без id и времени.
+func wantChanges(t *testing.T, op string, got, want []model.Change) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s вернул %d записей, want %d: %+v", op, len(got), len(want), got)
+	}
+	for i := range want {
+		g := got[i]
+		if g.Time.IsZero() {
+			t.Errorf("%s[%d] без времени изменения", op, i)
+		}
+		g.ID, g.TaskID, g.Time = 0, 0, want[i].Time
+		if g != want[i] {
+			t.Errorf("%s[%d] = %+v, want %+v", op, i, g, want[i])
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+func testProjects(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	projects, err := s.Projects(ctx)
+	if err != nil {
+		t.Fatalf("Projects() error = %v", err)
+	}
+	if len(projects) != 1 || projects[0].ID != model.DefaultProject {
+		t.Fatalf("Projects() = %v, want только проект по умолчанию", projects)
+	}
+
+	web := mustProject(t, s, "web")
+	if err := s.UpdateProject(ctx, model.Project{ID: web, Name: "сайт", Description: "витрина"}); err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	projects, _ = s.Projects(ctx)
+	if len(projects) != 2 || projects[1] != (model.Project{ID: web, Name: "сайт", Description: "витрина"}) {
+		t.Errorf("Projects() после UpdateProject() = %v", projects)
+	}
+	_, err = s.NewProject(ctx, model.Project{})
+	wantErr(t, "NewProject() без названия", err, storage.ErrInvalid)
+	wantErr(t, "UpdateProject() несуществующего проекта", s.UpdateProject(ctx, model.Project{ID: 999, Name: "a"}), storage.ErrNotFound)
+
+	alice := mustUser(t, s, "alice")
+	for i := 0; i < 2; i++ {
+		if err := s.AddProjectMember(ctx, web, alice); err != nil {
+			t.Fatalf("AddProjectMember() error = %v", err)
+		}
+	}
+	members, err := s.ProjectMembers(ctx, web)
+	if err != nil || len(members) != 1 || members[0] != (model.User{ID: alice, Name: "alice"}) {
+		t.Errorf("ProjectMembers() = %v, %v, want alice", members, err)
+	}
+	wantErr(t, "AddProjectMember() неизвестного пользователя", s.AddProjectMember(ctx, web, 999), storage.ErrReferenced)
+	wantErr(t, "AddProjectMember() в неизвестный проект", s.AddProjectMember(ctx, 999, alice), storage.ErrReferenced)
+	if err := s.RemoveProjectMember(ctx, web, alice); err != nil {
+		t.Fatalf("RemoveProjectMember() error = %v", err)
+	}
+	wantErr(t, "RemoveProjectMember() повторно", s.RemoveProjectMember(ctx, web, alice), storage.ErrNotFound)
+
+	// Проект с задачами, даже из корзины, не удаляется
+	task := mustTask(t, s, model.Task{ProjectID: web})
+	if err := s.DeleteTask(ctx, task); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	wantErr(t, "DeleteProject() с задачей в корзине", s.DeleteProject(ctx, web), storage.ErrReferenced)
+	if _, err := s.PurgeTasks(ctx, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("PurgeTasks() error = %v", err)
+	}
+	if err := s.DeleteProject(ctx, web); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+	wantErr(t, "DeleteProject() повторно", s.DeleteProject(ctx, web), storage.ErrNotFound)
+	wantErr(t, "DeleteProject() проекта по умолчанию", s.DeleteProject(ctx, model.DefaultProject), storage.ErrConflict)
+}
+
+func testProjectScope(t *testing.T, s storage.Interface) {
+	web := mustProject(t, s, "web")
+	ctx := storage.WithProject(context.Background(), web)
+
+	// Без проекта в задаче и в контексте задача попадает в проект по умолчанию
+	common := mustTask(t, s, model.Task{Title: "отчёт", Due: time.Now().Add(-day)})
+	if got := getTask(t, s, common); got.ProjectID != model.DefaultProject {
+		t.Errorf("NewTask() без проекта: ProjectID = %d, want %d", got.ProjectID, model.DefaultProject)
+	}
+	own, err := s.NewTask(ctx, model.Task{Title: "отчёт", Due: time.Now().Add(-day)}, nil)
+	if err != nil {
+		t.Fatalf("NewTask() error = %v", err)
+	}
+	sub, err := s.NewTask(ctx, model.Task{ParentID: common, Title: "подзадача"}, nil)
+	if err != nil {
+		t.Fatalf("NewTask() error = %v", err)
+	}
+	_, err = s.NewTask(ctx, model.Task{Title: "a", ProjectID: 999}, nil)
+	wantErr(t, "NewTask() в неизвестном проекте", err, storage.ErrReferenced)
+
+	tasks, err := s.Tasks(ctx, model.TaskFilter{})
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	wantIDs(t, "Tasks() проекта", tasks, own, sub)
+	wantIDs(t, "Tasks() всех проектов", mustTasks(t, s, model.TaskFilter{}), common, own, sub)
+
+	tasks, err = s.OverdueTasks(ctx, 0)
+	if err != nil {
+		t.Fatalf("OverdueTasks() error = %v", err)
+	}
+	wantIDs(t, "OverdueTasks() проекта", tasks, own)
+	tasks, err = s.UnblockedTasks(ctx)
+	if err != nil {
+		t.Fatalf("UnblockedTasks() error = %v", err)
+	}
+	wantIDs(t, "UnblockedTasks() проекта", tasks, own, sub)
+	tasks, err = s.GetTasksByAuthor(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetTasksByAuthor() error = %v", err)
+	}
+	wantIDs(t, "GetTasksByAuthor() проекта", tasks, own, sub)
+	results, err := s.SearchTasks(ctx, "отчёт", 10)
+	if err != nil || len(results) != 1 || results[0].Task.ID != own {
+		t.Errorf("SearchTasks() проекта = %v, %v, want задачу %d", results, err, own)
+	}
+	// Подзадача задачи из другого проекта - корень дерева проекта
+	forest, err := s.TaskTree(ctx, 0)
+	if err != nil {
+		t.Fatalf("TaskTree() error = %v", err)
+	}
+	if len(forest) != 2 || forest[0].Task.ID != own || forest[1].Task.ID != sub {
+		t.Errorf("TaskTree() проекта = %v, want корни %d и %d", forest, own, sub)
+	}
+
+	// Перенос задачи в другой проект
+	task := getTask(t, s, own)
+	task.ProjectID = model.DefaultProject
+	if err := s.UpdateTask(ctx, task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	task = getTask(t, s, own)
+	if task.ProjectID != model.DefaultProject {
+		t.Errorf("UpdateTask() ProjectID = %d, want %d", task.ProjectID, model.DefaultProject)
+	}
+	// Без проекта в обновлении задача остаётся в своём проекте
+	task.ProjectID = 0
+	task.Title = "сводка"
+	if err := s.UpdateTask(ctx, task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := getTask(t, s, own); got.ProjectID != model.DefaultProject {
+		t.Errorf("UpdateTask() без проекта: ProjectID = %d, want %d", got.ProjectID, model.DefaultProject)
+	}
+	tasks, _ = s.Tasks(ctx, model.TaskFilter{})
+	wantIDs(t, "Tasks() проекта после переноса", tasks, sub)
+}
+
+func mustProject(t *testing.T, s storage.Interface, name string) int {
+	t.Helper()
+	id, err := s.NewProject(context.Background(), model.Project{Name: name})
+	if err != nil {
+		t.Fatalf("NewProject(%q) error = %v", name, err)
+	}
+	return id
+}
@@ -0,0 +1,204 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+func testLabels(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	bug, feature := mustLabel(t, s, "bug"), mustLabel(t, s, "feature")
+	labels, err := s.Labels(ctx)
+	if err != nil {
+		t.Fatalf("Labels() error = %v", err)
+	}
+	if len(labels) != 2 || labels[0].ID != bug || labels[1].ID != feature {
+		t.Fatalf("Labels() = %v, want bug и feature", labels)
+	}
+
+	_, err = s.NewLabel(ctx, model.Label{Name: ""})
+	wantErr(t, "NewLabel() без названия", err, storage.ErrInvalid)
+
+	if err := s.UpdateLabel(ctx, model.Label{ID: bug, Name: "ошибка"}); err != nil {
+		t.Fatalf("UpdateLabel() error = %v", err)
+	}
+	if labels, _ := s.Labels(ctx); labels[0].Name != "ошибка" {
+		t.Errorf("UpdateLabel() название = %q, want ошибка", labels[0].Name)
+	}
+	wantErr(t, "UpdateLabel() несуществующей метки", s.UpdateLabel(ctx, model.Label{ID: 999, Name: "a"}), storage.ErrNotFound)
+	wantErr(t, "UpdateLabel() без названия", s.UpdateLabel(ctx, model.Label{ID: bug}), storage.ErrInvalid)
+
+	task := mustTask(t, s, model.Task{})
+	for i := 0; i < 2; i++ {
+		if err := s.AttachLabel(ctx, task, bug); err != nil {
+			t.Fatalf("AttachLabel() error = %v", err)
+		}
+	}
+	if got := getTask(t, s, task).Labels; len(got) != 1 || got[0].ID != bug {
+		t.Errorf("AttachLabel() метки задачи = %v, want одна метка %d", got, bug)
+	}
+	wantErr(t, "AttachLabel() неизвестной метки", s.AttachLabel(ctx, task, 999), storage.ErrReferenced)
+	wantErr(t, "AttachLabel() к неизвестной задаче", s.AttachLabel(ctx, 999, bug), storage.ErrReferenced)
+
+	if err := s.DetachLabel(ctx, task, bug); err != nil {
+		t.Fatalf("DetachLabel() error = %v", err)
+	}
+	if got := getTask(t, s, task).Labels; len(got) != 0 {
+		t.Errorf("DetachLabel() метки задачи = %v, want пусто", got)
+	}
+	wantErr(t, "DetachLabel() повторно", s.DetachLabel(ctx, task, bug), storage.ErrNotFound)
+
+	// Удалённая метка пропадает и с задач
+	if err := s.AttachLabel(ctx, task, feature); err != nil {
+		t.Fatalf("AttachLabel() error = %v", err)
+	}
+	if err := s.DeleteLabel(ctx, feature); err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+	if got := getTask(t, s, task).Labels; len(got) != 0 {
+		t.Errorf("DeleteLabel() метка осталась на задаче: %v", got)
+	}
+	wantErr(t, "DeleteLabel() повторно", s.DeleteLabel(ctx, feature), storage.ErrNotFound)
+
+	// id удалённых меток не выдаются повторно
+	if id := mustLabel(t, s, "docs"); id <= feature {
+		t.Errorf("NewLabel() после удаления id = %d, want больше %d", id, feature)
+	}
+}
+
+func testComments(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	task := mustTask(t, s, model.Task{})
+	first, err := s.NewComment(ctx, model.Comment{TaskID: task, Content: "первый"})
+	if err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+	second, err := s.NewComment(ctx, model.Comment{TaskID: task, Content: "второй"})
+	if err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+
+	comments, err := s.Comments(ctx, task)
+	if err != nil {
+		t.Fatalf("Comments() error = %v", err)
+	}
+	if len(comments) != 2 || comments[0].ID != first || comments[1].ID != second {
+		t.Fatalf("Comments() = %v, want %d и %d по порядку", comments, first, second)
+	}
+	if c := comments[0]; c.Author.Name != "default" || c.Created.IsZero() || !c.Edited.IsZero() {
+		t.Errorf("Comments() первый комментарий = %+v", c)
+	}
+
+	if err := s.UpdateComment(ctx, model.Comment{ID: first, Content: "исправлен"}); err != nil {
+		t.Fatalf("UpdateComment() error = %v", err)
+	}
+	comments, _ = s.Comments(ctx, task)
+	if c := comments[0]; c.Content != "исправлен" || c.Edited.IsZero() {
+		t.Errorf("UpdateComment() комментарий = %+v", c)
+	}
+	wantErr(t, "UpdateComment() несуществующего", s.UpdateComment(ctx, model.Comment{ID: 999, Content: "a"}), storage.ErrNotFound)
+	wantErr(t, "UpdateComment() без текста", s.UpdateComment(ctx, model.Comment{ID: first}), storage.ErrInvalid)
+
+	_, err = s.NewComment(ctx, model.Comment{TaskID: 999, Content: "a"})
+	wantErr(t, "NewComment() к неизвестной задаче", err, storage.ErrReferenced)
+	_, err = s.NewComment(ctx, model.Comment{TaskID: task, Author: model.User{ID: 999}, Content: "a"})
+	wantErr(t, "NewComment() от неизвестного автора", err, storage.ErrReferenced)
+	_, err = s.NewComment(ctx, model.Comment{TaskID: task})
+	wantErr(t, "NewComment() без текста", err, storage.ErrInvalid)
+
+	if err := s.DeleteComment(ctx, first); err != nil {
+		t.Fatalf("DeleteComment() error = %v", err)
+	}
+	if comments, _ := s.Comments(ctx, task); len(comments) != 1 || comments[0].ID != second {
+		t.Errorf("Comments() после удаления = %v, want только %d", comments, second)
+	}
+	wantErr(t, "DeleteComment() повторно", s.DeleteComment(ctx, first), storage.ErrNotFound)
+}
+
+func testStatuses(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	statuses, err := s.Statuses(ctx)
+	if err != nil {
+		t.Fatalf("Statuses() error = %v", err)
+	}
+	want := []string{"backlog", "in progress", "review", "done"}
+	if len(statuses) != len(want) {
+		t.Fatalf("Statuses() вернул %d статусов, want %d", len(statuses), len(want))
+	}
+	for i, st := range statuses {
+		if st.ID != i+1 || st.Name != want[i] {
+			t.Errorf("Statuses()[%d] = %d %q, want %d %q", i, st.ID, st.Name, i+1, want[i])
+		}
+	}
+	transitions, err := s.Transitions(ctx)
+	if err != nil || len(transitions) == 0 {
+		t.Fatalf("Transitions() = %v, %v, want переходы по умолчанию", transitions, err)
+	}
+
+	next, err := s.NextStatuses(ctx, statusInProgress)
+	if err != nil {
+		t.Fatalf("NextStatuses() error = %v", err)
+	}
+	if len(next) != 2 || next[0].ID != statusBacklog || next[1].ID != statusReview {
+		t.Errorf("NextStatuses(in progress) = %v, want backlog и review", next)
+	}
+
+	blocked, err := s.NewStatus(ctx, model.Status{Name: "blocked", Position: 5})
+	if err != nil {
+		t.Fatalf("NewStatus() error = %v", err)
+	}
+	if blocked != 5 {
+		t.Errorf("NewStatus() id = %d, want 5", blocked)
+	}
+	_, err = s.NewStatus(ctx, model.Status{})
+	wantErr(t, "NewStatus() без названия", err, storage.ErrInvalid)
+
+	for i := 0; i < 2; i++ {
+		if err := s.AddTransition(ctx, statusInProgress, blocked); err != nil {
+			t.Fatalf("AddTransition() error = %v", err)
+		}
+	}
+	wantErr(t, "AddTransition() в неизвестный статус", s.AddTransition(ctx, statusInProgress, 999), storage.ErrReferenced)
+
+	task := mustTask(t, s, model.Task{StatusID: statusInProgress})
+	if err := s.UpdateTask(ctx, model.Task{ID: task, Version: version(t, s, task), Title: "a", StatusID: blocked}); err != nil {
+		t.Errorf("UpdateTask() по новому переходу error = %v", err)
+	}
+
+	if err := s.DeleteTransition(ctx, statusInProgress, blocked); err != nil {
+		t.Fatalf("DeleteTransition() error = %v", err)
+	}
+	wantErr(t, "DeleteTransition() повторно", s.DeleteTransition(ctx, statusInProgress, blocked), storage.ErrNotFound)
+}
+
+func testUsers(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	users, err := s.Users(ctx)
+	if err != nil {
+		t.Fatalf("Users() error = %v", err)
+	}
+	if len(users) != 1 || users[0].ID != 0 || users[0].Name != "default" {
+		t.Fatalf("Users() = %v, want только пользователь по умолчанию", users)
+	}
+
+	alice := mustUser(t, s, "alice")
+	if alice == 0 {
+		t.Errorf("NewUser() id = 0, занят пользователем по умолчанию")
+	}
+	if users, _ := s.Users(ctx); len(users) != 2 {
+		t.Errorf("Users() после NewUser() = %v", users)
+	}
+	_, err = s.NewUser(ctx, model.User{})
+	wantErr(t, "NewUser() без имени", err, storage.ErrInvalid)
+
+	own := mustTask(t, s, model.Task{AuthorID: alice})
+	mustTask(t, s, model.Task{})
+	tasks, err := s.GetTasksByAuthor(ctx, alice)
+	if err != nil {
+		t.Fatalf("GetTasksByAuthor() error = %v", err)
+	}
+	wantIDs(t, "GetTasksByAuthor()", tasks, own)
+}
@@ -0,0 +1,139 @@
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+func testSprints(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	now := time.Now()
+	next := mustSprint(t, s, model.Sprint{Name: "второй", Start: now.Add(day), End: now.Add(15 * day)})
+	cur := mustSprint(t, s, model.Sprint{Name: "первый", Start: now.Add(-day), End: now.Add(13 * day)})
+	web := mustProject(t, s, "web")
+	other := mustSprint(t, s, model.Sprint{ProjectID: web, Name: "сайт", Start: now, End: now.Add(day)})
+
+	sprints, err := s.Sprints(storage.WithProject(ctx, model.DefaultProject))
+	if err != nil {
+		t.Fatalf("Sprints() error = %v", err)
+	}
+	if len(sprints) != 2 || sprints[0].ID != cur || sprints[1].ID != next {
+		t.Fatalf("Sprints() = %v, want %d и %d по дате начала", sprints, cur, next)
+	}
+	if got := sprints[0]; got.Name != "первый" || got.ProjectID != model.DefaultProject ||
+		got.Start.Unix() != now.Add(-day).Unix() || got.End.Unix() != now.Add(13*day).Unix() {
+		t.Errorf("Sprints()[0] = %+v", got)
+	}
+	_, err = s.NewSprint(ctx, model.Sprint{Name: "наоборот", Start: now, End: now.Add(-day)})
+	wantErr(t, "NewSprint() с концом раньше начала", err, storage.ErrInvalid)
+	_, err = s.NewSprint(ctx, model.Sprint{ProjectID: 999, Name: "a", Start: now, End: now.Add(day)})
+	wantErr(t, "NewSprint() в неизвестном проекте", err, storage.ErrReferenced)
+
+	a := mustTask(t, s, model.Task{Title: "a"})
+	b := mustTask(t, s, model.Task{Title: "b"})
+	c := mustTask(t, s, model.Task{Title: "c"})
+	trashed := mustTask(t, s, model.Task{Title: "в корзине"})
+	for _, id := range []int{a, b, c, trashed, a} {
+		if err := s.AddToSprint(ctx, cur, id); err != nil {
+			t.Fatalf("AddToSprint(%d, %d) error = %v", cur, id, err)
+		}
+	}
+	// Повторное добавление ничего не меняет
+	if task := getTask(t, s, a); task.SprintID != cur || task.Version != 2 {
+		t.Errorf("AddToSprint() SprintID = %d, версия = %d, want %d и 2", task.SprintID, task.Version, cur)
+	}
+	if err := s.DeleteTask(ctx, trashed); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := s.CloseTask(ctx, a); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	if err := s.RemoveFromSprint(ctx, cur, b); err != nil {
+		t.Fatalf("RemoveFromSprint() error = %v", err)
+	}
+	wantIDs(t, "Tasks() спринта", mustTasks(t, s, model.TaskFilter{SprintID: cur}), a, c)
+
+	wantErr(t, "RemoveFromSprint() повторно", s.RemoveFromSprint(ctx, cur, b), storage.ErrNotFound)
+	wantErr(t, "AddToSprint() в неизвестный спринт", s.AddToSprint(ctx, 999, c), storage.ErrReferenced)
+	wantErr(t, "AddToSprint() неизвестной задачи", s.AddToSprint(ctx, cur, 999), storage.ErrNotFound)
+	wantErr(t, "AddToSprint() задачи в корзине", s.AddToSprint(ctx, cur, trashed), storage.ErrNotFound)
+	wantErr(t, "AddToSprint() в спринт другого проекта", s.AddToSprint(ctx, other, c), storage.ErrInvalid)
+
+	// Спринт уже начался: все задачи добавлены после начала
+	wantSummary(t, s, "SprintSummary() идущего спринта", cur, model.SprintSummary{Added: 4, Removed: 2, Completed: 1, Remaining: 1})
+
+	moved, err := s.CarryOver(ctx, cur, next)
+	if err != nil || moved != 1 {
+		t.Fatalf("CarryOver() = %d, %v, want 1", moved, err)
+	}
+	wantIDs(t, "Tasks() следующего спринта", mustTasks(t, s, model.TaskFilter{SprintID: next}), c)
+	changes, err := s.History(ctx, c)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	want := model.Change{Field: model.FieldSprint, Before: strconv.Itoa(cur), After: strconv.Itoa(next)}
+	if last := changes[len(changes)-1]; last.Field != want.Field || last.Before != want.Before || last.After != want.After {
+		t.Errorf("History() после CarryOver() последняя запись = %+v, want %+v", last, want)
+	}
+	if moved, err := s.CarryOver(ctx, cur, next); err != nil || moved != 0 {
+		t.Errorf("CarryOver() повторно = %d, %v, want 0", moved, err)
+	}
+	_, err = s.CarryOver(ctx, cur, cur)
+	wantErr(t, "CarryOver() в тот же спринт", err, storage.ErrInvalid)
+	_, err = s.CarryOver(ctx, cur, other)
+	wantErr(t, "CarryOver() в спринт другого проекта", err, storage.ErrInvalid)
+	_, err = s.CarryOver(ctx, 999, next)
+	wantErr(t, "CarryOver() из неизвестного спринта", err, storage.ErrNotFound)
+	_, err = s.CarryOver(ctx, cur, 999)
+	wantErr(t, "CarryOver() в неизвестный спринт", err, storage.ErrReferenced)
+
+	wantSummary(t, s, "SprintSummary() после переноса", cur, model.SprintSummary{Added: 4, Removed: 3, Completed: 1})
+	// Спринт ещё не начался: перенесённые задачи взяты в него с начала
+	wantSummary(t, s, "SprintSummary() будущего спринта", next, model.SprintSummary{Committed: 1, Remaining: 1})
+	_, err = s.SprintSummary(ctx, 999)
+	wantErr(t, "SprintSummary() неизвестного спринта", err, storage.ErrNotFound)
+
+	// Спринты у каждого проекта свои: при переносе в другой проект задача убирается из спринта
+	task := getTask(t, s, c)
+	task.ProjectID = web
+	if err := s.UpdateTask(ctx, task); err != nil {
+		t.Fatalf("UpdateTask() с другим проектом error = %v", err)
+	}
+	if task := getTask(t, s, c); task.ProjectID != web || task.SprintID != 0 {
+		t.Errorf("UpdateTask() с другим проектом проект = %d, спринт = %d, want %d и 0", task.ProjectID, task.SprintID, web)
+	}
+	wantIDs(t, "Tasks() спринта после переноса задачи в другой проект", mustTasks(t, s, model.TaskFilter{SprintID: next}))
+
+	wantErr(t, "DeleteProject() со спринтом", s.DeleteProject(ctx, web), storage.ErrReferenced)
+}
+
+// wantSummary проверяет счётчики итогов спринта.
+func wantSummary(t *testing.T, s storage.Interface, op string, sprintID int, want model.SprintSummary) {
+	t.Helper()
+	got, err := s.SprintSummary(context.Background(), sprintID)
+	if err != nil {
+		t.Fatalf("%s error = %v", op, err)
+	}
+	if got.Sprint.ID != sprintID {
+		t.Errorf("%s спринт = %d, want %d", op, got.Sprint.ID, sprintID)
+	}
+	got.Sprint = model.Sprint{}
+	if got != want {
+		t.Errorf("%s = %+v, want %+v", op, got, want)
+	}
+}
+
+// mustSprint создаёт спринт и возвращает его id.
+func mustSprint(t *testing.T, s storage.Interface, sprint model.Sprint) int {
+	t.Helper()
+	id, err := s.NewSprint(context.Background(), sprint)
+	if err != nil {
+		t.Fatalf("NewSprint(%q) error = %v", sprint.Name, err)
+	}
+	return id
+}
@@ -0,0 +1,235 @@
+// Пакет storagetest содержит общий набор тестов для реализаций storage.Interface.
+// Каждая реализация запускает его из своих тестов:
+//
+//	func TestStorage(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Interface { return New() })
+//	}
+//
+// Так все хранилища проверяются одними и теми же тестами и не расходятся в поведении.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Factory создаёт пустое хранилище для одного теста: только пользователь
+// по умолчанию (id 0) и процесс работы по умолчанию. Освободить ресурсы
+// фабрика может через t.Cleanup.
+type Factory func(t *testing.T) storage.Interface
+
+// Статусы и переходы процесса работы по умолчанию.
+const (
+	statusBacklog    = 1
+	statusInProgress = 2
+	statusReview     = 3
+	statusDone       = 4
+)
+
+// Run запускает все тесты набора, каждый на новом хранилище.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Interface)
+	}{
+		{"NewTask", testNewTask},
+		{"UpdateTask", testUpdateTask},
+		{"Version", testVersion},
+		{"DeleteTask", testDeleteTask},
+		{"Trash", testTrash},
+		{"CloseReopen", testCloseReopen},
+		{"Filter", testFilter},
+		{"Pagination", testPagination},
+		{"Subtasks", testSubtasks},
+		{"Board", testBoard},
+		{"Dependencies", testDependencies},
+		{"Overdue", testOverdue},
+		{"Search", testSearch},
+		{"Labels", testLabels},
+		{"Comments", testComments},
+		{"Statuses", testStatuses},
+		{"Users", testUsers},
+		{"Projects", testProjects},
+		{"ProjectScope", testProjectScope},
+		{"Sprints", testSprints},
+		{"History", testHistory},
+		{"Tx", testTx},
+		{"Context", testContext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+// testContext проверяет, что отменённый контекст прерывает операции.
+func testContext(t *testing.T, s storage.Interface) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.Tasks(ctx, model.TaskFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Tasks() с отменённым контекстом error = %v, want context.Canceled", err)
+	}
+	if _, err := s.NewTask(ctx, model.Task{Title: "a"}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("NewTask() с отменённым контекстом error = %v, want context.Canceled", err)
+	}
+	if tasks := mustTasks(t, s, model.TaskFilter{}); len(tasks) != 0 {
+		t.Errorf("NewTask() с отменённым контекстом создал задачу")
+	}
+}
+
+// testTx проверяет, что WithTx применяет изменения целиком или не применяет вовсе.
+func testTx(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	fail := errors.New("отмена")
+
+	err := s.WithTx(ctx, func(tx storage.Interface) error {
+		label, err := tx.NewLabel(ctx, model.Label{Name: "bug"})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.NewTask(ctx, model.Task{Title: "откатится"}, []int{label}); err != nil {
+			return err
+		}
+		// Внутри транзакции изменения уже видны
+		if tasks, err := tx.Tasks(ctx, model.TaskFilter{}); err != nil || len(tasks) != 1 {
+			t.Errorf("Tasks() внутри WithTx() = %v, %v, want одну задачу", tasks, err)
+		}
+		return fail
+	})
+	wantErr(t, "WithTx()", err, fail)
+	if tasks := mustTasks(t, s, model.TaskFilter{}); len(tasks) != 0 {
+		t.Errorf("Tasks() после отката = %v, want пусто", tasks)
+	}
+	if labels, err := s.Labels(ctx); err != nil || len(labels) != 0 {
+		t.Errorf("Labels() после отката = %v, %v, want пусто", labels, err)
+	}
+
+	// Ошибка вложенной транзакции отменяет только её изменения
+	var kept int
+	err = s.WithTx(ctx, func(tx storage.Interface) error {
+		var err error
+		kept, err = tx.NewTask(ctx, model.Task{Title: "останется"}, nil)
+		if err != nil {
+			return err
+		}
+		err = tx.WithTx(ctx, func(inner storage.Interface) error {
+			if _, err := inner.NewTask(ctx, model.Task{Title: "откатится"}, nil); err != nil {
+				return err
+			}
+			return fail
+		})
+		wantErr(t, "WithTx() вложенная", err, fail)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error = %v", err)
+	}
+	wantIDs(t, "Tasks() после вложенного отката", mustTasks(t, s, model.TaskFilter{}), kept)
+}
+
+// wantErr проверяет, что err является ошибкой target.
+func wantErr(t *testing.T, op string, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("%s error = %v, want %v", op, err, target)
+	}
+}
+
+// mustTask создаёт задачу и возвращает её id.
+func mustTask(t *testing.T, s storage.Interface, task model.Task, labels ...int) int {
+	t.Helper()
+	if task.Title == "" {
+		task.Title = "задача"
+	}
+	id, err := s.NewTask(context.Background(), task, labels)
+	if err != nil {
+		t.Fatalf("NewTask(%q) error = %v", task.Title, err)
+	}
+	return id
+}
+
+// mustTasks возвращает задачи по фильтру.
+func mustTasks(t *testing.T, s storage.Interface, f model.TaskFilter) []model.Task {
+	t.Helper()
+	tasks, err := s.Tasks(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Tasks(%+v) error = %v", f, err)
+	}
+	return tasks
+}
+
+// getTask возвращает задачу по id.
+func getTask(t *testing.T, s storage.Interface, id int) model.Task {
+	t.Helper()
+	tasks := mustTasks(t, s, model.TaskFilter{TaskID: id})
+	if len(tasks) != 1 {
+		t.Fatalf("Tasks(TaskID: %d) вернул %d задач, want 1", id, len(tasks))
+	}
+	return tasks[0]
+}
+
+// version возвращает текущую версию задачи для UpdateTask.
+func version(t *testing.T, s storage.Interface, id int) int {
+	t.Helper()
+	return getTask(t, s, id).Version
+}
+
+// mustLabel создаёт метку и возвращает её id.
+func mustLabel(t *testing.T, s storage.Interface, name string) int {
+	t.Helper()
+	id, err := s.NewLabel(context.Background(), model.Label{Name: name})
+	if err != nil {
+		t.Fatalf("NewLabel(%q) error = %v", name, err)
+	}
+	return id
+}
+
+// mustUser создаёт пользователя и возвращает его id.
+func mustUser(t *testing.T, s storage.Interface, name string) int {
+	t.Helper()
+	id, err := s.NewUser(context.Background(), model.User{Name: name})
+	if err != nil {
+		t.Fatalf("NewUser(%q) error = %v", name, err)
+	}
+	return id
+}
+
+// ids возвращает id задач по порядку.
+func ids(tasks []model.Task) []int {
+	result := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, t.ID)
+	}
+	return result
+}
+
+// equalIDs сравнивает списки id.
+func equalIDs(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// wantIDs проверяет id задач и их порядок.
+func wantIDs(t *testing.T, op string, tasks []model.Task, want ...int) {
+	t.Helper()
+	if got := ids(tasks); !equalIDs(got, want) {
+		t.Errorf("%s = %v, want %v", op, got, want)
+	}
+}
+
+// day — сутки, для сроков выполнения и границ дат.
+const day = 24 * time.Hour
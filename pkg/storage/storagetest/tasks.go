@@ -0,0 +1,563 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+func testNewTask(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	due := time.Now().Add(day)
+	before := time.Now().Add(-time.Minute)
+	id := mustTask(t, s, model.Task{
+		Title:    "первая",
+		Content:  "текст",
+		Priority: model.PriorityHigh,
+		Due:      due,
+	})
+
+	got := getTask(t, s, id)
+	switch {
+	case got.Title != "первая" || got.Content != "текст":
+		t.Errorf("NewTask() сохранил %q/%q, want первая/текст", got.Title, got.Content)
+	case got.Priority != model.PriorityHigh:
+		t.Errorf("NewTask() приоритет = %d, want %d", got.Priority, model.PriorityHigh)
+	case got.Due.Unix() != due.Unix():
+		t.Errorf("NewTask() срок = %v, want %v", got.Due, due)
+	case got.StatusID != statusBacklog || got.Status != "backlog":
+		t.Errorf("NewTask() статус = %d %q, want первый статус процесса", got.StatusID, got.Status)
+	case got.Opened.Before(before) || got.IsClosed():
+		t.Errorf("NewTask() opened = %v, closed = %v", got.Opened, got.Closed)
+	}
+
+	second := mustTask(t, s, model.Task{StatusID: statusReview})
+	if second <= id {
+		t.Errorf("NewTask() id = %d после %d, want больше", second, id)
+	}
+	if got := getTask(t, s, second); got.Status != "review" {
+		t.Errorf("NewTask() со статусом review получил статус %q", got.Status)
+	}
+
+	bug, feature := mustLabel(t, s, "bug"), mustLabel(t, s, "feature")
+	labeled := mustTask(t, s, model.Task{}, feature, bug)
+	if got := getTask(t, s, labeled).Labels; len(got) != 2 || got[0].Name != "bug" || got[1].Name != "feature" {
+		t.Errorf("NewTask() метки = %v, want [bug feature]", got)
+	}
+
+	_, err := s.NewTask(ctx, model.Task{Title: " "}, nil)
+	wantErr(t, "NewTask() без заголовка", err, storage.ErrInvalid)
+	_, err = s.NewTask(ctx, model.Task{Title: "a", Priority: 7}, nil)
+	wantErr(t, "NewTask() с неизвестным приоритетом", err, storage.ErrInvalid)
+	_, err = s.NewTask(ctx, model.Task{Title: "a", AuthorID: 999}, nil)
+	wantErr(t, "NewTask() с неизвестным автором", err, storage.ErrReferenced)
+	_, err = s.NewTask(ctx, model.Task{Title: "a", ParentID: 999}, nil)
+	wantErr(t, "NewTask() с неизвестным родителем", err, storage.ErrReferenced)
+	_, err = s.NewTask(ctx, model.Task{Title: "a"}, []int{bug, 999})
+	wantErr(t, "NewTask() с неизвестной меткой", err, storage.ErrReferenced)
+
+	// Задача с неизвестной меткой не должна сохраниться даже частично
+	wantIDs(t, "Tasks() после ошибок создания", mustTasks(t, s, model.TaskFilter{}), id, second, labeled)
+}
+
+func testUpdateTask(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	user := mustUser(t, s, "исполнитель")
+	id := mustTask(t, s, model.Task{Title: "старый"})
+	opened := getTask(t, s, id).Opened
+	due := time.Now().Add(2 * day)
+
+	err := s.UpdateTask(ctx, model.Task{
+		ID:         id,
+		Version:    version(t, s, id),
+		Title:      "новый",
+		Content:    "описание",
+		AssignedID: user,
+		Priority:   model.PriorityCritical,
+		Due:        due,
+		StatusID:   statusInProgress,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	got := getTask(t, s, id)
+	switch {
+	case got.Title != "новый" || got.Content != "описание" || got.AssignedID != user:
+		t.Errorf("UpdateTask() сохранил %+v", got)
+	case got.Priority != model.PriorityCritical || got.Due.Unix() != due.Unix():
+		t.Errorf("UpdateTask() приоритет = %d, срок = %v", got.Priority, got.Due)
+	case got.StatusID != statusInProgress || got.Status != "in progress":
+		t.Errorf("UpdateTask() статус = %d %q, want in progress", got.StatusID, got.Status)
+	case got.Opened.Unix() != opened.Unix():
+		t.Errorf("UpdateTask() изменил время создания: %v, want %v", got.Opened, opened)
+	}
+
+	// StatusID = 0 оставляет текущий статус
+	if err := s.UpdateTask(ctx, model.Task{ID: id, Version: version(t, s, id), Title: "новый"}); err != nil {
+		t.Fatalf("UpdateTask() без статуса error = %v", err)
+	}
+	if got := getTask(t, s, id); got.StatusID != statusInProgress {
+		t.Errorf("UpdateTask() без статуса сменил статус на %d", got.StatusID)
+	}
+
+	// Из in progress в done перехода нет
+	err = s.UpdateTask(ctx, model.Task{ID: id, Version: version(t, s, id), Title: "новый", StatusID: statusDone})
+	var trErr *model.TransitionError
+	if !errors.As(err, &trErr) || trErr.FromID != statusInProgress || trErr.ToID != statusDone {
+		t.Errorf("UpdateTask() с запрещённым переходом error = %v, want *TransitionError", err)
+	}
+	wantErr(t, "UpdateTask() с запрещённым переходом", err, storage.ErrConflict)
+
+	// Обновление не открывает закрытую задачу
+	if _, err := s.CloseTask(ctx, id); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	if err := s.UpdateTask(ctx, model.Task{ID: id, Version: version(t, s, id), Title: "закрытая"}); err != nil {
+		t.Fatalf("UpdateTask() закрытой задачи error = %v", err)
+	}
+	if !getTask(t, s, id).IsClosed() {
+		t.Errorf("UpdateTask() открыл закрытую задачу")
+	}
+
+	parent := mustTask(t, s, model.Task{})
+	child := mustTask(t, s, model.Task{ParentID: parent})
+	err = s.UpdateTask(ctx, model.Task{ID: parent, Version: version(t, s, parent), Title: "a", ParentID: child})
+	wantErr(t, "UpdateTask() с циклом подзадач", err, storage.ErrInvalid)
+	err = s.UpdateTask(ctx, model.Task{ID: parent, Version: version(t, s, parent), Title: "a", ParentID: parent})
+	wantErr(t, "UpdateTask() с собой в качестве родителя", err, storage.ErrInvalid)
+	err = s.UpdateTask(ctx, model.Task{ID: parent, Version: version(t, s, parent), Title: "a", ParentID: 999})
+	wantErr(t, "UpdateTask() с неизвестным родителем", err, storage.ErrReferenced)
+	err = s.UpdateTask(ctx, model.Task{ID: parent, Version: version(t, s, parent), Title: ""})
+	wantErr(t, "UpdateTask() без заголовка", err, storage.ErrInvalid)
+	err = s.UpdateTask(ctx, model.Task{ID: 999, Title: "a"})
+	wantErr(t, "UpdateTask() несуществующей задачи", err, storage.ErrNotFound)
+	err = s.UpdateTask(ctx, model.Task{ID: 999, Title: "a", StatusID: statusBacklog})
+	wantErr(t, "UpdateTask() несуществующей задачи со статусом", err, storage.ErrNotFound)
+}
+
+func testVersion(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := mustTask(t, s, model.Task{Title: "первая версия"})
+	loaded := getTask(t, s, id)
+	if loaded.Version == 0 {
+		t.Fatalf("NewTask() версия = 0, want начальную версию")
+	}
+
+	// Первое сохранение по прочитанной версии проходит и увеличивает версию
+	mine := loaded
+	mine.Title = "моя правка"
+	if err := s.UpdateTask(ctx, mine); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	current := getTask(t, s, id)
+	if current.Version <= loaded.Version {
+		t.Errorf("UpdateTask() версия = %d, want больше %d", current.Version, loaded.Version)
+	}
+
+	// Второе сохранение по той же версии - конфликт, задача не меняется
+	theirs := loaded
+	theirs.Title = "чужая правка"
+	err := s.UpdateTask(ctx, theirs)
+	wantErr(t, "UpdateTask() по устаревшей версии", err, model.ErrVersionConflict)
+	wantErr(t, "UpdateTask() по устаревшей версии", err, storage.ErrConflict)
+	if got := getTask(t, s, id); got.Title != "моя правка" || got.Version != current.Version {
+		t.Errorf("UpdateTask() по устаревшей версии изменил задачу: %q, версия %d", got.Title, got.Version)
+	}
+
+	// Закрытие и переоткрытие тоже меняют задачу
+	if _, err := s.CloseTask(ctx, id); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	closed := getTask(t, s, id).Version
+	if closed <= current.Version {
+		t.Errorf("CloseTask() версия = %d, want больше %d", closed, current.Version)
+	}
+	if _, err := s.CloseTask(ctx, id); err != nil {
+		t.Fatalf("CloseTask() повторно error = %v", err)
+	}
+	if got := getTask(t, s, id).Version; got != closed {
+		t.Errorf("CloseTask() повторно изменил версию: %d, want %d", got, closed)
+	}
+	wantErr(t, "UpdateTask() после закрытия", s.UpdateTask(ctx, current), model.ErrVersionConflict)
+	if err := s.ReopenTask(ctx, id); err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	if got := getTask(t, s, id).Version; got <= closed {
+		t.Errorf("ReopenTask() версия = %d, want больше %d", got, closed)
+	}
+}
+
+func testDeleteTask(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	label := mustLabel(t, s, "bug")
+	parent := mustTask(t, s, model.Task{Title: "удаляемая"}, label)
+	child := mustTask(t, s, model.Task{ParentID: parent})
+	blocker := mustTask(t, s, model.Task{})
+	if err := s.AddDependency(ctx, child, parent); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if err := s.AddDependency(ctx, child, blocker); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if _, err := s.NewComment(ctx, model.Comment{TaskID: parent, Content: "к удалению"}); err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+	results, err := s.SearchTasks(ctx, "удаляемая", 10)
+	if err != nil || len(results) != 1 || results[0].Task.ID != parent {
+		t.Fatalf("SearchTasks() до удаления = %v, %v, want задачу %d", results, err, parent)
+	}
+
+	if err := s.DeleteTask(ctx, parent); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	wantIDs(t, "Tasks() после удаления", mustTasks(t, s, model.TaskFilter{}), child, blocker)
+	trash := mustTasks(t, s, model.TaskFilter{Trash: true})
+	wantIDs(t, "Tasks(Trash) после удаления", trash, parent)
+	if !trash[0].IsDeleted() || len(trash[0].Labels) != 1 {
+		t.Errorf("Tasks(Trash) = %+v, want задачу с временем удаления и меткой", trash[0])
+	}
+	if got := getTask(t, s, child); got.ParentID != parent {
+		t.Errorf("DeleteTask() родителя: ParentID подзадачи = %d, want %d", got.ParentID, parent)
+	}
+	subtasks, err := s.Subtasks(ctx, parent)
+	if err != nil || len(subtasks) != 1 {
+		t.Errorf("Subtasks() задачи в корзине = %v, %v, want подзадачу", subtasks, err)
+	}
+	blockers, err := s.Blockers(ctx, child)
+	if err != nil {
+		t.Fatalf("Blockers() error = %v", err)
+	}
+	wantIDs(t, "Blockers() после удаления блокирующей задачи", blockers, blocker)
+	results, err = s.SearchTasks(ctx, "удаляемая", 10)
+	if err != nil || len(results) != 0 {
+		t.Errorf("SearchTasks() задачи в корзине = %v, %v, want пусто", results, err)
+	}
+	comments, err := s.Comments(ctx, parent)
+	if err != nil || len(comments) != 1 {
+		t.Errorf("Comments() задачи в корзине = %v, %v, want комментарий", comments, err)
+	}
+
+	wantErr(t, "DeleteTask() повторно", s.DeleteTask(ctx, parent), storage.ErrNotFound)
+	wantErr(t, "UpdateTask() задачи в корзине", s.UpdateTask(ctx, trash[0]), storage.ErrNotFound)
+	_, err = s.CloseTask(ctx, parent)
+	wantErr(t, "CloseTask() задачи в корзине", err, storage.ErrNotFound)
+	wantErr(t, "DeleteTask() несуществующей задачи", s.DeleteTask(ctx, 999), storage.ErrNotFound)
+}
+
+func testTrash(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	parent := mustTask(t, s, model.Task{})
+	child := mustTask(t, s, model.Task{ParentID: parent})
+	if _, err := s.NewComment(ctx, model.Comment{TaskID: parent, Content: "к удалению"}); err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+
+	wantErr(t, "RestoreTask() задачи не из корзины", s.RestoreTask(ctx, parent), storage.ErrNotFound)
+	if err := s.DeleteTask(ctx, parent); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if err := s.RestoreTask(ctx, parent); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if got := getTask(t, s, parent); got.IsDeleted() {
+		t.Errorf("RestoreTask(): задача осталась в корзине, Deleted = %v", got.Deleted)
+	}
+	wantIDs(t, "Tasks(Trash) после восстановления", mustTasks(t, s, model.TaskFilter{Trash: true}))
+
+	if err := s.DeleteTask(ctx, parent); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	n, err := s.PurgeTasks(ctx, time.Now().Add(-time.Hour))
+	if err != nil || n != 0 {
+		t.Errorf("PurgeTasks(час назад) = %d, %v, want 0", n, err)
+	}
+	n, err = s.PurgeTasks(ctx, time.Now().Add(time.Second))
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeTasks(сейчас) = %d, %v, want 1", n, err)
+	}
+	wantIDs(t, "Tasks(Trash) после очистки", mustTasks(t, s, model.TaskFilter{Trash: true}))
+	if got := getTask(t, s, child); got.ParentID != 0 {
+		t.Errorf("PurgeTasks() родителя: у подзадачи остался ParentID %d", got.ParentID)
+	}
+	comments, err := s.Comments(ctx, parent)
+	if err != nil || len(comments) != 0 {
+		t.Errorf("Comments() удалённой задачи = %v, %v, want пусто", comments, err)
+	}
+	wantErr(t, "RestoreTask() после очистки", s.RestoreTask(ctx, parent), storage.ErrNotFound)
+}
+
+func testCloseReopen(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := mustTask(t, s, model.Task{})
+	before := time.Now().Add(-time.Minute)
+
+	if _, err := s.CloseTask(ctx, id); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	closed := getTask(t, s, id).Closed
+	if closed.Before(before) {
+		t.Fatalf("CloseTask() время выполнения = %v", closed)
+	}
+
+	// Повторное закрытие не меняет время выполнения
+	if _, err := s.CloseTask(ctx, id); err != nil {
+		t.Fatalf("CloseTask() повторно error = %v", err)
+	}
+	if got := getTask(t, s, id).Closed; got.Unix() != closed.Unix() {
+		t.Errorf("CloseTask() повторно изменил время выполнения: %v, want %v", got, closed)
+	}
+
+	if err := s.ReopenTask(ctx, id); err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	if getTask(t, s, id).IsClosed() {
+		t.Errorf("ReopenTask() задача осталась закрытой")
+	}
+
+	_, err := s.CloseTask(ctx, 999)
+	wantErr(t, "CloseTask() несуществующей задачи", err, storage.ErrNotFound)
+	wantErr(t, "ReopenTask() несуществующей задачи", s.ReopenTask(ctx, 999), storage.ErrNotFound)
+}
+
+func testFilter(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice, bob := mustUser(t, s, "alice"), mustUser(t, s, "bob")
+	bug, feature := mustLabel(t, s, "bug"), mustLabel(t, s, "feature")
+	t1 := mustTask(t, s, model.Task{AuthorID: alice, AssignedID: bob}, bug)
+	t2 := mustTask(t, s, model.Task{AuthorID: bob, AssignedID: alice}, bug, feature)
+	t3 := mustTask(t, s, model.Task{AuthorID: alice})
+	if _, err := s.CloseTask(ctx, t3); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []int
+	}{
+		{"все", model.TaskFilter{}, []int{t1, t2, t3}},
+		{"по id", model.TaskFilter{TaskID: t2}, []int{t2}},
+		{"по автору", model.TaskFilter{AuthorID: alice}, []int{t1, t3}},
+		{"по исполнителю", model.TaskFilter{AssignedID: alice}, []int{t2}},
+		{"любая из меток", model.TaskFilter{LabelsAny: []int{bug, feature}}, []int{t1, t2}},
+		{"одна метка", model.TaskFilter{LabelsAny: []int{feature}}, []int{t2}},
+		{"все метки", model.TaskFilter{LabelsAll: []int{bug, feature}}, []int{t2}},
+		{"открытые", model.TaskFilter{State: model.StateOpen}, []int{t1, t2}},
+		{"закрытые", model.TaskFilter{State: model.StateClosed}, []int{t3}},
+		{"созданы в периоде", model.TaskFilter{OpenedFrom: now.Add(-time.Hour), OpenedTo: now.Add(time.Hour)}, []int{t1, t2, t3}},
+		{"созданы позже", model.TaskFilter{OpenedFrom: now.Add(time.Hour)}, nil},
+		{"созданы раньше", model.TaskFilter{OpenedTo: now.Add(-time.Hour)}, nil},
+		{"закрыты после", model.TaskFilter{ClosedFrom: now.Add(-time.Hour)}, []int{t3}},
+		{"закрыты до", model.TaskFilter{ClosedTo: now.Add(time.Hour)}, []int{t3}},
+		{"несколько условий", model.TaskFilter{AuthorID: alice, LabelsAny: []int{bug}, State: model.StateOpen}, []int{t1}},
+	}
+	for _, tt := range tests {
+		wantIDs(t, "Tasks() "+tt.name, mustTasks(t, s, tt.filter), tt.want...)
+	}
+
+	for _, f := range []model.TaskFilter{
+		{Sort: "priority"},
+		{Limit: -1},
+		{Offset: -1},
+		{After: t1, Before: t2},
+		{State: 5},
+		{OpenedFrom: now, OpenedTo: now.Add(-time.Hour)},
+	} {
+		_, err := s.Tasks(ctx, f)
+		wantErr(t, "Tasks() с некорректным фильтром", err, storage.ErrInvalid)
+	}
+}
+
+func testPagination(t *testing.T, s storage.Interface) {
+	var id = make(map[string]int)
+	for _, title := range []string{"d", "b", "e", "a", "c"} {
+		id[title] = mustTask(t, s, model.Task{Title: title})
+	}
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []string
+	}{
+		{"по id", model.TaskFilter{}, []string{"d", "b", "e", "a", "c"}},
+		{"по id по убыванию", model.TaskFilter{Desc: true}, []string{"c", "a", "e", "b", "d"}},
+		{"по заголовку", model.TaskFilter{Sort: model.SortTitle}, []string{"a", "b", "c", "d", "e"}},
+		{"по заголовку по убыванию", model.TaskFilter{Sort: model.SortTitle, Desc: true}, []string{"e", "d", "c", "b", "a"}},
+		{"первая страница", model.TaskFilter{Sort: model.SortTitle, Limit: 2}, []string{"a", "b"}},
+		{"отступ", model.TaskFilter{Sort: model.SortTitle, Limit: 2, Offset: 2}, []string{"c", "d"}},
+		{"отступ за концом", model.TaskFilter{Offset: 10}, nil},
+		{"после", model.TaskFilter{Sort: model.SortTitle, Limit: 2, After: id["b"]}, []string{"c", "d"}},
+		{"после последней", model.TaskFilter{Sort: model.SortTitle, After: id["e"]}, nil},
+		{"перед", model.TaskFilter{Sort: model.SortTitle, Limit: 2, Before: id["d"]}, []string{"b", "c"}},
+		{"перед первой", model.TaskFilter{Sort: model.SortTitle, Before: id["a"]}, nil},
+		{"после по убыванию", model.TaskFilter{Sort: model.SortTitle, Desc: true, Limit: 2, After: id["d"]}, []string{"c", "b"}},
+		{"перед по убыванию", model.TaskFilter{Sort: model.SortTitle, Desc: true, Limit: 2, Before: id["b"]}, []string{"d", "c"}},
+		{"перед по id", model.TaskFilter{Limit: 2, Before: id["c"]}, []string{"e", "a"}},
+	}
+	for _, tt := range tests {
+		want := make([]int, 0, len(tt.want))
+		for _, title := range tt.want {
+			want = append(want, id[title])
+		}
+		wantIDs(t, "Tasks() "+tt.name, mustTasks(t, s, tt.filter), want...)
+	}
+}
+
+func testSubtasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	root := mustTask(t, s, model.Task{})
+	a := mustTask(t, s, model.Task{ParentID: root})
+	b := mustTask(t, s, model.Task{ParentID: root})
+	grandchild := mustTask(t, s, model.Task{ParentID: a})
+	other := mustTask(t, s, model.Task{})
+
+	children, err := s.Subtasks(ctx, root)
+	if err != nil {
+		t.Fatalf("Subtasks() error = %v", err)
+	}
+	wantIDs(t, "Subtasks()", children, a, b)
+	children, err = s.Subtasks(ctx, other)
+	if err != nil || len(children) != 0 {
+		t.Errorf("Subtasks() задачи без подзадач = %v, %v", ids(children), err)
+	}
+
+	if _, err := s.CloseTask(ctx, grandchild); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	tree, err := s.TaskTree(ctx, root)
+	if err != nil {
+		t.Fatalf("TaskTree() error = %v", err)
+	}
+	if len(tree) != 1 || tree[0].Task.ID != root || len(tree[0].Children) != 2 {
+		t.Fatalf("TaskTree() = %v, want одно дерево с корнем %d и двумя подзадачами", tree, root)
+	}
+	if closed, total := tree[0].Progress(); closed != 1 || total != 3 {
+		t.Errorf("TaskTree() прогресс = %d/%d, want 1/3", closed, total)
+	}
+
+	forest, err := s.TaskTree(ctx, 0)
+	if err != nil {
+		t.Fatalf("TaskTree(0) error = %v", err)
+	}
+	if len(forest) != 2 || forest[0].Task.ID != root || forest[1].Task.ID != other {
+		t.Errorf("TaskTree(0) вернул %d деревьев, want корни %d и %d", len(forest), root, other)
+	}
+
+	tree, err = s.TaskTree(ctx, 999)
+	if err != nil || len(tree) != 0 {
+		t.Errorf("TaskTree() несуществующей задачи = %v, %v, want пусто", tree, err)
+	}
+}
+
+func testDependencies(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	task := mustTask(t, s, model.Task{})
+	low := mustTask(t, s, model.Task{Priority: model.PriorityLow})
+	high := mustTask(t, s, model.Task{Priority: model.PriorityHigh})
+
+	for _, blocker := range []int{low, high, low} {
+		if err := s.AddDependency(ctx, task, blocker); err != nil {
+			t.Fatalf("AddDependency(%d, %d) error = %v", task, blocker, err)
+		}
+	}
+	blockers, err := s.Blockers(ctx, task)
+	if err != nil {
+		t.Fatalf("Blockers() error = %v", err)
+	}
+	wantIDs(t, "Blockers()", blockers, low, high)
+
+	err = s.AddDependency(ctx, low, task)
+	wantErr(t, "AddDependency() с циклом", err, storage.ErrInvalid)
+	if !errors.Is(err, model.ErrDependencyCycle) {
+		t.Errorf("AddDependency() с циклом error = %v, want ErrDependencyCycle", err)
+	}
+	wantErr(t, "AddDependency() на себя", s.AddDependency(ctx, task, task), storage.ErrInvalid)
+	wantErr(t, "AddDependency() с неизвестной задачей", s.AddDependency(ctx, task, 999), storage.ErrReferenced)
+
+	unblocked, err := s.UnblockedTasks(ctx)
+	if err != nil {
+		t.Fatalf("UnblockedTasks() error = %v", err)
+	}
+	wantIDs(t, "UnblockedTasks()", unblocked, high, low)
+
+	freed, err := s.CloseTask(ctx, low)
+	if err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	wantIDs(t, "CloseTask() первой блокирующей задачи", freed)
+	freed, err = s.CloseTask(ctx, high)
+	if err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+	wantIDs(t, "CloseTask() последней блокирующей задачи", freed, task)
+
+	unblocked, err = s.UnblockedTasks(ctx)
+	if err != nil {
+		t.Fatalf("UnblockedTasks() error = %v", err)
+	}
+	wantIDs(t, "UnblockedTasks() после закрытия", unblocked, task)
+
+	if err := s.RemoveDependency(ctx, task, low); err != nil {
+		t.Fatalf("RemoveDependency() error = %v", err)
+	}
+	wantErr(t, "RemoveDependency() повторно", s.RemoveDependency(ctx, task, low), storage.ErrNotFound)
+}
+
+func testOverdue(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	now := time.Now()
+	late := mustTask(t, s, model.Task{Due: now.Add(-2 * day)})
+	soon := mustTask(t, s, model.Task{Due: now.Add(time.Hour), Priority: model.PriorityCritical})
+	mustTask(t, s, model.Task{Due: now.Add(10 * day)})
+	mustTask(t, s, model.Task{})
+	done := mustTask(t, s, model.Task{Due: now.Add(-day)})
+	later := mustTask(t, s, model.Task{Due: now.Add(-3 * day)})
+	if _, err := s.CloseTask(ctx, done); err != nil {
+		t.Fatalf("CloseTask() error = %v", err)
+	}
+
+	tasks, err := s.OverdueTasks(ctx, day)
+	if err != nil {
+		t.Fatalf("OverdueTasks() error = %v", err)
+	}
+	wantIDs(t, "OverdueTasks()", tasks, soon, later, late)
+}
+
+func testSearch(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	inTitle := mustTask(t, s, model.Task{Title: "Database migration", Content: "move tables"})
+	inContent := mustTask(t, s, model.Task{Title: "Fix login", Content: "database timeout"})
+	mustTask(t, s, model.Task{Title: "Write docs", Content: "readme"})
+
+	results, err := s.SearchTasks(ctx, "database", 10)
+	if err != nil {
+		t.Fatalf("SearchTasks() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Task.ID != inTitle || results[1].Task.ID != inContent {
+		t.Fatalf("SearchTasks() вернул %d результатов, want %d и %d по релевантности", len(results), inTitle, inContent)
+	}
+	for _, r := range results {
+		if r.Rank <= 0 {
+			t.Errorf("SearchTasks() релевантность задачи %d = %v", r.Task.ID, r.Rank)
+		}
+		if r.Snippet == "" {
+			t.Errorf("SearchTasks() пустой фрагмент у задачи %d", r.Task.ID)
+		}
+	}
+
+	if results, err := s.SearchTasks(ctx, "database", 1); err != nil || len(results) != 1 {
+		t.Errorf("SearchTasks() с limit 1 вернул %d результатов, %v", len(results), err)
+	}
+	// limit 0 - без ограничения, как в Tasks
+	if results, err := s.SearchTasks(ctx, "database", 0); err != nil || len(results) != 2 {
+		t.Errorf("SearchTasks() с limit 0 вернул %d результатов, %v, want 2", len(results), err)
+	}
+	if results, err := s.SearchTasks(ctx, "kubernetes", 10); err != nil || len(results) != 0 {
+		t.Errorf("SearchTasks() без совпадений вернул %d результатов, %v", len(results), err)
+	}
+}
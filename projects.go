@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Функция для загрузки названия текущего проекта при запуске
+func loadCurrentProject(storage storage.Interface) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	projects, err := storage.Projects(ctx)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении проектов:", err)
+		return
+	}
+	for _, p := range projects {
+		if p.ID == currentProject.ID {
+			currentProject = p
+		}
+	}
+}
+
+// Функция для работы с проектами: создание, изменение, удаление и участники
+// Экран обновляется после каждого действия, Enter - возврат в главное меню
+func manageProjects(scanner *bufio.Scanner, storage storage.Interface) {
+	for {
+		printProjects(storage)
+
+		fmt.Println("\n1. Создать проект")
+		fmt.Println("2. Изменить проект")
+		fmt.Println("3. Удалить проект")
+		fmt.Println("4. Участники проекта")
+		fmt.Println("5. Добавить участника")
+		fmt.Println("6. Исключить участника")
+		fmt.Print("\nВведите номер действия (Enter - в главное меню): ")
+		scanner.Scan()
+
+		switch strings.TrimSpace(scanner.Text()) {
+		case "1":
+			createProject(scanner, storage)
+			waitForEnter(scanner)
+		case "2":
+			editProject(scanner, storage)
+			waitForEnter(scanner)
+		case "3":
+			deleteProject(scanner, storage)
+			waitForEnter(scanner)
+		case "4":
+			printMembers(scanner, storage)
+			waitForEnter(scanner)
+		case "5":
+			addMember(scanner, storage)
+			waitForEnter(scanner)
+		case "6":
+			removeMember(scanner, storage)
+			waitForEnter(scanner)
+		case "":
+			return
+		default:
+			fmt.Println("\n🔴 Некорректный ввод, попробуйте снова.")
+		}
+	}
+}
+
+// Функция для вывода списка проектов, текущий проект отмечен
+// Возвращает проекты, nil - если список получить не удалось
+func printProjects(storage storage.Interface) []model.Project {
+	ctx, cancel := operation()
+	defer cancel()
+
+	projects, err := storage.Projects(ctx)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении проектов:", err)
+		return nil
+	}
+
+	fmt.Println("\n============ПРОЕКТЫ============")
+	for _, p := range projects {
+		mark := "  "
+		if p.ID == currentProject.ID {
+			mark = "👉"
+		}
+		fmt.Printf("%s ID: %d | %s", mark, p.ID, p.Name)
+		if p.Description != "" {
+			fmt.Printf(" — %s", p.Description)
+		}
+		fmt.Println()
+	}
+	fmt.Println("-------------------------------")
+	return projects
+}
+
+// Функция для смены текущего проекта сессии
+func switchProject(scanner *bufio.Scanner, storage storage.Interface) {
+	projects := printProjects(storage)
+	if projects == nil {
+		return
+	}
+
+	fmt.Print("\n📁 Введите ID проекта: ")
+	scanner.Scan()
+	projectID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	for _, p := range projects {
+		if p.ID == projectID {
+			currentProject = p
+			fmt.Printf("\n✅ Текущий проект: %s\n", p.Name)
+			fmt.Println("-------------------------------")
+			return
+		}
+	}
+	fmt.Println("\n⚠️  Проект не найден.")
+}
+
+// Функция для ввода названия и описания проекта
+func scanProject(scanner *bufio.Scanner) model.Project {
+	fmt.Print("\n📁 Введите название проекта: ")
+	scanner.Scan()
+	name := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("\n📝 Введите описание проекта (или оставьте пустым): ")
+	scanner.Scan()
+	description := strings.TrimSpace(scanner.Text())
+
+	return model.Project{Name: name, Description: description}
+}
+
+// Функция для создания проекта
+func createProject(scanner *bufio.Scanner, storage storage.Interface) {
+	project := scanProject(scanner)
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	id, err := storage.NewProject(ctx, project)
+	if err != nil {
+		printError("\n🔴 Ошибка при создании проекта:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n✅ Проект успешно создан! ID: %d\n", id)
+	fmt.Println("-------------------------------")
+}
+
+// Функция для изменения названия и описания проекта
+func editProject(scanner *bufio.Scanner, storage storage.Interface) {
+	projectID, ok := scanProjectID(scanner)
+	if !ok {
+		return
+	}
+	project := scanProject(scanner)
+	project.ID = projectID
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.UpdateProject(ctx, project); err != nil {
+		printError("\n🔴 Ошибка при изменении проекта:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+	if project.ID == currentProject.ID {
+		currentProject = project
+	}
+
+	fmt.Println("\n✅ Проект изменён!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для удаления проекта без задач
+func deleteProject(scanner *bufio.Scanner, storage storage.Interface) {
+	projectID, ok := scanProjectID(scanner)
+	if !ok {
+		return
+	}
+	if projectID == currentProject.ID {
+		fmt.Println("\n⚠️  Нельзя удалить текущий проект, сначала смените его.")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.DeleteProject(ctx, projectID); err != nil {
+		printError("\n🔴 Ошибка при удалении проекта (в нём не должно быть задач, в том числе в корзине):", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Проект удалён!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для вывода участников проекта
+func printMembers(scanner *bufio.Scanner, storage storage.Interface) {
+	projectID, ok := scanProjectID(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	users, err := storage.ProjectMembers(ctx, projectID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении участников проекта:", err)
+		return
+	}
+
+	fmt.Println("-------------------------------")
+	if len(users) == 0 {
+		fmt.Println("\n⚠️  В проекте пока нет участников.")
+		return
+	}
+	fmt.Println("\n👥 Участники проекта:")
+	for _, user := range users {
+		fmt.Printf("ID: %d | Имя: %s\n", user.ID, user.Name)
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для добавления участника в проект
+func addMember(scanner *bufio.Scanner, storage storage.Interface) {
+	projectID, userID, ok := scanMember(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.AddProjectMember(ctx, projectID, userID); err != nil {
+		printError("\n🔴 Ошибка при добавлении участника:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Участник добавлен в проект!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для исключения участника из проекта
+func removeMember(scanner *bufio.Scanner, storage storage.Interface) {
+	projectID, userID, ok := scanMember(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.RemoveProjectMember(ctx, projectID, userID); err != nil {
+		printError("\n🔴 Ошибка при исключении участника:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Участник исключён из проекта!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для ввода ID проекта
+func scanProjectID(scanner *bufio.Scanner) (int, bool) {
+	fmt.Print("\n📁 Введите ID проекта: ")
+	scanner.Scan()
+	projectID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID проекта")
+		return 0, false
+	}
+	return projectID, true
+}
+
+// Функция для ввода ID проекта и ID пользователя
+func scanMember(scanner *bufio.Scanner) (int, int, bool) {
+	projectID, ok := scanProjectID(scanner)
+	if !ok {
+		return 0, 0, false
+	}
+
+	fmt.Print("\n👤 Введите ID пользователя: ")
+	scanner.Scan()
+	userID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID пользователя")
+		return 0, 0, false
+	}
+	return projectID, userID, true
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Максимальное число результатов полнотекстового поиска
+const searchLimit = 20
+
+// Функция для поиска задач по нескольким критериям сразу
+// Пустой ввод - критерий не учитывается
+func searchTasks(scanner *bufio.Scanner, storage storage.Interface) {
+	var f model.TaskFilter
+	var ok bool
+
+	fmt.Println("-------------------------------")
+	fmt.Println("🔎 Заполните нужные критерии, Enter - пропустить")
+
+	if f.AuthorID, ok = scanOptionalID(scanner, "👤 ID автора"); !ok {
+		return
+	}
+	if f.AssignedID, ok = scanOptionalID(scanner, "🎯 ID исполнителя"); !ok {
+		return
+	}
+	if f.LabelsAny, ok = scanIDList(scanner, "🏷️  ID меток через запятую (любая из них)"); !ok {
+		return
+	}
+	if f.LabelsAll, ok = scanIDList(scanner, "🏷️  ID меток через запятую (все сразу)"); !ok {
+		return
+	}
+	if f.OpenedFrom, ok = scanDate(scanner, "📅 Создана не раньше ДД.ММ.ГГГГ", false); !ok {
+		return
+	}
+	if f.OpenedTo, ok = scanDate(scanner, "📅 Создана не позже ДД.ММ.ГГГГ", true); !ok {
+		return
+	}
+	if f.ClosedFrom, ok = scanDate(scanner, "✅ Закрыта не раньше ДД.ММ.ГГГГ", false); !ok {
+		return
+	}
+	if f.ClosedTo, ok = scanDate(scanner, "✅ Закрыта не позже ДД.ММ.ГГГГ", true); !ok {
+		return
+	}
+	f.State = scanState(scanner)
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.Tasks(ctx, f)
+	if err != nil {
+		fmt.Println("-------------------------------")
+		printError("\n🔴 Ошибка при поиске задач:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("-------------------------------")
+		fmt.Println("\n⚠️  Подходящих задач не найдено.")
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n📋 Найдено задач: %d\n", len(tasks))
+	for _, task := range tasks {
+		fmt.Println("-------------------------------")
+		for _, line := range taskLines(task) {
+			fmt.Println(line)
+		}
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для полнотекстового поиска задач по названию и описанию
+func fullTextSearch(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Println("-------------------------------")
+	fmt.Print("\n🔎 Введите слова для поиска: ")
+	scanner.Scan()
+	query := strings.TrimSpace(scanner.Text())
+	if query == "" {
+		fmt.Println("\n❌ Ошибка: Пустой запрос")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	results, err := storage.SearchTasks(ctx, query, searchLimit)
+	if err != nil {
+		fmt.Println("-------------------------------")
+		printError("\n🔴 Ошибка при поиске задач:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	if len(results) == 0 {
+		fmt.Println("-------------------------------")
+		fmt.Println("\n⚠️  Ничего не найдено.")
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n📋 Найдено задач: %d\n", len(results))
+	for _, r := range results {
+		fmt.Println("-------------------------------")
+		fmt.Printf("🆔 ID: %d | 📌 %s | 🚦 %s | ⭐ %.3f\n", r.Task.ID, r.Task.Title, r.Task.Status, r.Rank)
+		fmt.Printf("   %s\n", r.Snippet)
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для ввода необязательного ID, пустой ввод - 0
+func scanOptionalID(scanner *bufio.Scanner, prompt string) (int, bool) {
+	fmt.Print("\n" + prompt + ": ")
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(input)
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// Функция для ввода списка ID через запятую
+func scanIDList(scanner *bufio.Scanner, prompt string) ([]int, bool) {
+	fmt.Print("\n" + prompt + ": ")
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return nil, true
+	}
+
+	var ids []int
+	for _, str := range strings.Split(input, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(str))
+		if err != nil {
+			fmt.Println("\n❌ Ошибка: Некорректный ID")
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+// Функция для ввода необязательной даты, пустой ввод - нулевое время
+// endOfDay - вернуть последнюю секунду дня, для верхней границы диапазона
+func scanDate(scanner *bufio.Scanner, prompt string, endOfDay bool) (time.Time, bool) {
+	fmt.Print("\n" + prompt + ": ")
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return time.Time{}, true
+	}
+	date, err := time.ParseInLocation("02.01.2006", input, time.Local)
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректная дата")
+		return time.Time{}, false
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return date, true
+}
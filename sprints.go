@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Функция для работы со спринтами текущего проекта: планирование задач и итоги
+// Экран обновляется после каждого действия, Enter - возврат в главное меню
+func sprints(scanner *bufio.Scanner, storage storage.Interface) {
+	for {
+		list, ok := printSprints(storage)
+		if !ok {
+			return
+		}
+
+		fmt.Println("\n1. Создать спринт")
+		fmt.Println("2. Задачи и итоги спринта")
+		fmt.Println("3. Добавить задачу в спринт")
+		fmt.Println("4. Убрать задачу из спринта")
+		fmt.Println("5. Перенести незавершённые задачи в другой спринт")
+		fmt.Print("\nВведите номер действия (Enter - в главное меню): ")
+		scanner.Scan()
+
+		switch strings.TrimSpace(scanner.Text()) {
+		case "1":
+			createSprint(scanner, storage)
+			waitForEnter(scanner)
+		case "2":
+			sprintReport(scanner, storage)
+			waitForEnter(scanner)
+		case "3":
+			addToSprint(scanner, storage)
+			waitForEnter(scanner)
+		case "4":
+			removeFromSprint(scanner, storage)
+			waitForEnter(scanner)
+		case "5":
+			carryOver(scanner, storage, list)
+			waitForEnter(scanner)
+		case "":
+			return
+		default:
+			fmt.Println("\n🔴 Некорректный ввод, попробуйте снова.")
+		}
+	}
+}
+
+// Функция для вывода спринтов текущего проекта, идущий спринт отмечен
+// Возвращает спринты и false, если список получить не удалось
+func printSprints(storage storage.Interface) ([]model.Sprint, bool) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	list, err := storage.Sprints(ctx)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении спринтов:", err)
+		return nil, false
+	}
+
+	fmt.Println("\n============СПРИНТЫ============")
+	if len(list) == 0 {
+		fmt.Println("⚠️  В проекте пока нет спринтов.")
+	}
+	now := time.Now()
+	for _, s := range list {
+		mark := "  "
+		if !now.Before(s.Start) && now.Before(s.End) {
+			mark = "▶️ "
+		}
+		fmt.Printf("%s ID: %d | %s | %s\n", mark, s.ID, s.Name, sprintDates(s))
+	}
+	fmt.Println("-------------------------------")
+	return list, true
+}
+
+// Функция для вывода дат спринта
+func sprintDates(s model.Sprint) string {
+	return s.Start.Format("02.01.2006") + " — " + s.End.Format("02.01.2006")
+}
+
+// Функция для создания спринта в текущем проекте
+func createSprint(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🏃 Введите название спринта: ")
+	scanner.Scan()
+	name := strings.TrimSpace(scanner.Text())
+
+	// Спринт идёт с начала первого дня до конца последнего
+	start, ok := scanDate(scanner, "📅 Введите дату начала ДД.ММ.ГГГГ", false)
+	if !ok {
+		return
+	}
+	end, ok := scanDate(scanner, "📅 Введите дату окончания ДД.ММ.ГГГГ", true)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	id, err := storage.NewSprint(ctx, model.Sprint{Name: name, Start: start, End: end})
+	if err != nil {
+		printError("\n🔴 Ошибка при создании спринта:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n✅ Спринт успешно создан! ID: %d\n", id)
+	fmt.Println("-------------------------------")
+}
+
+// Функция для вывода задач спринта и итогов по истории задач
+func sprintReport(scanner *bufio.Scanner, storage storage.Interface) {
+	sprintID, ok := scanSprintID(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	summary, err := storage.SprintSummary(ctx, sprintID)
+	if err != nil {
+		printError("\n🔴 Ошибка при получении итогов спринта:", err)
+		return
+	}
+	tasks, err := storage.Tasks(ctx, model.TaskFilter{SprintID: sprintID})
+	if err != nil {
+		printError("\n🔴 Ошибка при получении задач спринта:", err)
+		return
+	}
+
+	fmt.Printf("\n🏃 %s | %s\n", summary.Sprint.Name, sprintDates(summary.Sprint))
+	fmt.Println("-------------------------------")
+	if len(tasks) == 0 {
+		fmt.Println("⚠️  В спринте нет задач.")
+	}
+	for _, task := range tasks {
+		fmt.Printf("%s | 🚦 %s\n", boardCard(task), task.Status)
+	}
+	fmt.Println("-------------------------------")
+	fmt.Printf("📌 Взято в спринт к началу: %d\n", summary.Committed)
+	fmt.Printf("➕ Добавлено после начала: %d\n", summary.Added)
+	fmt.Printf("➖ Убрано из спринта: %d\n", summary.Removed)
+	fmt.Printf("✅ Выполнено: %d\n", summary.Completed)
+	fmt.Printf("⏳ Осталось: %d\n", summary.Remaining)
+	fmt.Println("-------------------------------")
+}
+
+// Функция для добавления задачи в спринт, задача из другого спринта переносится
+func addToSprint(scanner *bufio.Scanner, storage storage.Interface) {
+	sprintID, taskID, ok := scanSprintTask(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.AddToSprint(ctx, sprintID, taskID); err != nil {
+		printError("\n🔴 Ошибка при добавлении задачи в спринт:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Задача добавлена в спринт!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для исключения задачи из спринта
+func removeFromSprint(scanner *bufio.Scanner, storage storage.Interface) {
+	sprintID, taskID, ok := scanSprintTask(scanner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.RemoveFromSprint(ctx, sprintID, taskID); err != nil {
+		printError("\n🔴 Ошибка при исключении задачи из спринта:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Println("\n✅ Задача убрана из спринта!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для переноса незакрытых задач спринта в другой спринт
+// По умолчанию задачи переносятся в следующий по дате начала спринт
+func carryOver(scanner *bufio.Scanner, storage storage.Interface, list []model.Sprint) {
+	fromID, ok := scanSprintID(scanner)
+	if !ok {
+		return
+	}
+
+	var next *model.Sprint
+	for i, s := range list {
+		if s.ID == fromID && i+1 < len(list) {
+			next = &list[i+1]
+		}
+	}
+	prompt := "🏃 Введите ID спринта, куда перенести задачи"
+	if next != nil {
+		prompt += fmt.Sprintf(" (Enter - следующий: %s)", next.Name)
+	}
+	toID, ok := scanOptionalID(scanner, prompt)
+	if !ok {
+		return
+	}
+	if toID == 0 {
+		if next == nil {
+			fmt.Println("\n⚠️  Следующего спринта нет, сначала создайте его.")
+			return
+		}
+		toID = next.ID
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	moved, err := storage.CarryOver(ctx, fromID, toID)
+	if err != nil {
+		printError("\n🔴 Ошибка при переносе задач:", err)
+		fmt.Println("-------------------------------")
+		return
+	}
+
+	fmt.Printf("\n✅ Перенесено задач: %d\n", moved)
+	fmt.Println("-------------------------------")
+}
+
+// Функция для ввода ID спринта
+func scanSprintID(scanner *bufio.Scanner) (int, bool) {
+	fmt.Print("\n🏃 Введите ID спринта: ")
+	scanner.Scan()
+	sprintID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID спринта")
+		return 0, false
+	}
+	return sprintID, true
+}
+
+// Функция для ввода ID спринта и ID задачи
+func scanSprintTask(scanner *bufio.Scanner) (int, int, bool) {
+	sprintID, ok := scanSprintID(scanner)
+	if !ok {
+		return 0, 0, false
+	}
+
+	fmt.Print("\n🆔 Введите ID задачи: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return 0, 0, false
+	}
+	return sprintID, taskID, true
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-meneger/pkg/model"
+	"task-meneger/pkg/storage"
+)
+
+// Срок хранения задач в корзине по умолчанию, в днях
+const trashRetentionDays = 30
+
+// Функция для работы с корзиной: просмотр, восстановление и очистка
+// Экран обновляется после каждого действия, Enter - возврат в главное меню
+func trash(scanner *bufio.Scanner, storage storage.Interface) {
+	for {
+		printTrash(storage)
+
+		fmt.Println("\n1. Восстановить задачу")
+		fmt.Println("2. Очистить корзину от старых задач")
+		fmt.Print("\nВведите номер действия (Enter - в главное меню): ")
+		scanner.Scan()
+
+		switch strings.TrimSpace(scanner.Text()) {
+		case "1":
+			restoreTask(scanner, storage)
+			waitForEnter(scanner)
+		case "2":
+			purgeTrash(scanner, storage)
+			waitForEnter(scanner)
+		case "":
+			return
+		default:
+			fmt.Println("\n🔴 Некорректный ввод, попробуйте снова.")
+		}
+	}
+}
+
+// Функция для вывода задач в корзине
+func printTrash(storage storage.Interface) {
+	ctx, cancel := operation()
+	defer cancel()
+
+	tasks, err := storage.Tasks(ctx, model.TaskFilter{Trash: true})
+	if err != nil {
+		printError("\n🔴 Ошибка при получении корзины:", err)
+		return
+	}
+
+	fmt.Println("\n============КОРЗИНА============")
+	if len(tasks) == 0 {
+		fmt.Println("🗑️  Корзина пуста.")
+		return
+	}
+	for _, task := range tasks {
+		fmt.Println("-------------------------------")
+		for _, line := range taskLines(task) {
+			fmt.Println(line)
+		}
+		fmt.Printf("🗑️  Удалена: %s\n", task.Deleted.Format("02.01.2006 15:04"))
+	}
+	fmt.Println("-------------------------------")
+}
+
+// Функция для восстановления задачи из корзины
+func restoreTask(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Print("\n🆔 Введите ID задачи для восстановления: ")
+	scanner.Scan()
+	taskID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("\n❌ Ошибка: Некорректный ID")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	if err := storage.RestoreTask(ctx, taskID); err != nil {
+		printError("\n🔴 Ошибка при восстановлении задачи:", err)
+		return
+	}
+
+	fmt.Println("\n✅ Задача восстановлена!")
+	fmt.Println("-------------------------------")
+}
+
+// Функция для окончательного удаления задач, которые лежат в корзине дольше срока хранения
+func purgeTrash(scanner *bufio.Scanner, storage storage.Interface) {
+	fmt.Printf("\n📅 Удалить задачи, которые в корзине дольше N дней (Enter - %d): ", trashRetentionDays)
+	scanner.Scan()
+	days := trashRetentionDays
+	if input := strings.TrimSpace(scanner.Text()); input != "" {
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 0 {
+			fmt.Println("\n❌ Ошибка: Некорректное число дней")
+			return
+		}
+		days = n
+	}
+
+	fmt.Print("\n⚠️  Задачи будут удалены навсегда вместе с комментариями. Продолжить? (y/N): ")
+	scanner.Scan()
+	if !strings.EqualFold(strings.TrimSpace(scanner.Text()), "y") {
+		fmt.Println("\n↩️  Очистка отменена.")
+		return
+	}
+
+	ctx, cancel := operation()
+	defer cancel()
+
+	n, err := storage.PurgeTasks(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		printError("\n🔴 Ошибка при очистке корзины:", err)
+		return
+	}
+
+	fmt.Printf("\n✅ Удалено задач: %d\n", n)
+	fmt.Println("-------------------------------")
+}